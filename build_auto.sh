@@ -32,7 +32,6 @@ EZMQ_BUILD_MODE="release"
 EZMQ_WITH_SECURITY=true
 
 ZMQ_LIBSODIUM="yes"
-IS_SECURED="secure"
 
 install_dependencies() {
     # download required tool chain for cross compilation [arm/arm64/armhf]
@@ -181,36 +180,40 @@ build_x86_and_64() {
     cd $PROJECT_ROOT/src/go/
     #build ezmq SDK
     cd ./ezmq
-    go build -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" 
+    go build -tags="${EZMQ_BUILD_MODE}" 
     go install
     
     #build samples
     cd ../samples
     if [ ${EZMQ_WITH_SECURITY} = true ]; then
-        go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber_secured.go
-        go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher_secured.go  
-    else
-        go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber.go
-        go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher.go  
+        go build -a -tags="${EZMQ_BUILD_MODE}" subscriber_secured.go
+        go build -a -tags="${EZMQ_BUILD_MODE}" publisher_secured.go  
+        go build -a -tags="${EZMQ_BUILD_MODE}" broker_secured.go
+        go build -a -tags="${EZMQ_BUILD_MODE}" keygen_secured.go
     fi
+    go build -a -tags="${EZMQ_BUILD_MODE}" subscriber.go
+    go build -a -tags="${EZMQ_BUILD_MODE}" publisher.go  
+    go build -a -tags="${EZMQ_BUILD_MODE}" broker.go
 }
 
 build_arm() {
     cd $PROJECT_ROOT/src/go/
     #build ezmq SDK
     cd ./ezmq
-    CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" 
+    CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -tags="${EZMQ_BUILD_MODE}" 
     CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go install
     #build samples
     cd ../samples
     
     if [ ${EZMQ_WITH_SECURITY} = true ]; then
-        CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber_secured.go
-        CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher_secured.go 
-    else
-        CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber.go
-        CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher.go
+        CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" subscriber_secured.go
+        CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" publisher_secured.go 
+        CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" broker_secured.go
+        CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" keygen_secured.go
     fi
+    CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" subscriber.go
+    CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" publisher.go
+    CGO_ENABLED=1 CC=arm-linux-gnueabi-gcc CXX=arm-linux-gnueabi-g++ GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" broker.go
     
 }
 
@@ -218,68 +221,76 @@ build_arm64() {
     cd $PROJECT_ROOT/src/go/
     #build ezmq SDK
     cd ./ezmq
-    CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" 
+    CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -tags="${EZMQ_BUILD_MODE}" 
     CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go install
     #build samples
     cd ../samples
     if [ ${EZMQ_WITH_SECURITY} = true ]; then
-        CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}"subscriber_secured.go
-        CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher_secured.go
-    else
-        CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber.go
-        CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher.go
+        CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE}" subscriber_secured.go
+        CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE}" publisher_secured.go
+        CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE}" broker_secured.go
+        CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE}" keygen_secured.go
     fi
+    CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE}" subscriber.go
+    CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE}" publisher.go
+    CGO_ENABLED=1 CC=/usr/bin/aarch64-linux-gnu-gcc-4.8 CXX=/usr/bin/aarch64-linux-gnu-g++-4.8 GOOS=linux GOARCH=arm64 go build -a -tags="${EZMQ_BUILD_MODE}" broker.go
 }
 
 build_armhf() {
     cd $PROJECT_ROOT/src/go/
     #build ezmq SDK
     cd ./ezmq
-    CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}"
+    CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -tags="${EZMQ_BUILD_MODE}"
     CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go install
     #build samples
     cd ../samples
     if [ ${EZMQ_WITH_SECURITY} = true ]; then
-        CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber_secured.go
-        CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher_secured.go 
-    else
-        CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber.go
-        CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher.go
-    fi  
+        CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE}" subscriber_secured.go
+        CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE}" publisher_secured.go 
+        CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE}" broker_secured.go
+        CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE}" keygen_secured.go
+    fi
+    CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE}" subscriber.go
+    CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE}" publisher.go
+    CGO_LDFLAGS+='-Bstatic -lzmq -lprotobuf -Bdynamic -lstdc++ -lm' GOOS=linux GOARCH=arm CGO_ENABLED=1 CC=arm-linux-gnueabihf-gcc-4.8 CXX=arm-linux-gnueabihf-g++-4.8 go build -a -tags="${EZMQ_BUILD_MODE}" broker.go
 }
 
 build_armhf_native() {
     cd $PROJECT_ROOT/src/go/
     #build ezmq SDK
     cd ./ezmq
-    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}"
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -tags="${EZMQ_BUILD_MODE}"
     CGO_ENABLED=1 GOOS=linux GOARCH=arm go install
     #build samples
     cd ../samples
     if [ ${EZMQ_WITH_SECURITY} = true ]; then
-        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber_secured.go
-        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher_secured.go 
-    else
-        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber.go
-        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher.go
+        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" subscriber_secured.go
+        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" publisher_secured.go 
+        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" broker_secured.go
+        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" keygen_secured.go
     fi
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" subscriber.go
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" publisher.go
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" broker.go
 }
 
 build_armhf_qemu() {
     cd $PROJECT_ROOT/src/go/
     #build ezmq SDK
     cd ./ezmq
-    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" 
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -tags="${EZMQ_BUILD_MODE}" 
     CGO_ENABLED=1 GOOS=linux GOARCH=arm go install
     #build samples
     cd ../samples
     if [ ${EZMQ_WITH_SECURITY} = true ]; then
-        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber_secured.go
-        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher_secured.go 
-    else
-        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" subscriber.go
-        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE} ${IS_SECURED}" publisher.go
+        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" subscriber_secured.go
+        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" publisher_secured.go 
+        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" broker_secured.go
+        CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" keygen_secured.go
     fi
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" subscriber.go
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" publisher.go
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQ_BUILD_MODE}" broker.go
 }
 
 clean_ezmq() {
@@ -302,7 +313,7 @@ usage() {
     echo "  --target_arch=[x86|x86_64|arm|arm64|armhf|armhf-qemu|armhf-native] :  Choose Target Architecture"
     echo "  --with_dependencies=[true|false](default: false)                   :  Build ezmq along with dependencies [zmq and protobuf]"
     echo "  --build_mode=[release|debug](default: release)                     :  Build ezmq library and samples in release or debug mode"
-    echo "  --with_security=[true|false](default: true)                        :  Build ezmq library with or without Security feature"
+    echo "  --with_security=[true|false](default: true)                        :  Build ZeroMQ with or without libsodium and secured samples"
     echo "  -c                                                                 :  Clean ezmq Repository and its dependencies"
     echo "  -h / --help                                                        :  Display help and exit [Be careful it will also remove GOPATH:src, pkg and bin]"
     echo -e "${GREEN}Notes: ${NO_COLOUR}"
@@ -322,7 +333,6 @@ build_ezmq() {
     fi
     if [ ${EZMQ_WITH_SECURITY} = false ]; then
         ZMQ_LIBSODIUM="no"
-        IS_SECURED="unsecure"
     fi
     cd $PROJECT_ROOT
     if [ -d "./src/go" ] ; then
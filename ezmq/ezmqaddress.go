@@ -0,0 +1,155 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"net"
+	"strings"
+)
+
+// Allow and deny lists of peer addresses. Entries are IP networks keyed by
+// their CIDR notation.
+type addressFilter struct {
+	allowed map[string]*net.IPNet
+	denied  map[string]*net.IPNet
+}
+
+func newAddressFilter() *addressFilter {
+	filter := &addressFilter{}
+	filter.allowed = make(map[string]*net.IPNet)
+	filter.denied = make(map[string]*net.IPNet)
+	return filter
+}
+
+// Parse IP address or CIDR into network. Single IP address is a network of
+// only that address.
+func parseAddress(address string) (*net.IPNet, bool) {
+	if strings.Contains(address, "/") {
+		_, network, err := net.ParseCIDR(address)
+		return network, nil == err
+	}
+	ip := net.ParseIP(address)
+	if nil == ip {
+		return nil, false
+	}
+	if ipv4 := ip.To4(); nil != ipv4 {
+		return &net.IPNet{IP: ipv4, Mask: net.CIDRMask(32, 32)}, true
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, true
+}
+
+func containsAddress(networks map[string]*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check address of peer. Denied addresses are rejected, and if allow list is
+// not empty only addresses in it are accepted. Peers on IPC and inproc
+// endpoints have no IP address, they are not checked.
+func (filter *addressFilter) check(address string) (string, bool) {
+	ip := net.ParseIP(address)
+	if nil == ip {
+		return "", true
+	}
+	if containsAddress(filter.denied, ip) {
+		return "address is denied", false
+	}
+	if len(filter.allowed) > 0 && false == containsAddress(filter.allowed, ip) {
+		return "address is not allowed", false
+	}
+	return "", true
+}
+
+// Allow subscribers from the given IP address or CIDR, e.g. 192.168.1.0/24.
+//
+// Note:
+// (1) Once an address is allowed, subscribers from other addresses are
+// rejected. Denied addresses are rejected even if they are allowed.
+//
+// (2) Addresses can be changed while publisher is running. Changes apply to
+// new connections, existing connections are not closed.
+//
+// (3) Rejected connections are reported to callback set by
+// SetAuthFailureCallback API.
+//
+// (4) Only TCP peers are checked, subscribers on IPC and inproc endpoints are
+// always allowed.
+//
+// (5) Addresses are checked by authentication of publisher. Notes of
+// AddClientKey API on other ZAP handlers apply to this API as well.
+func (pubInstance *EZMQPublisher) AllowAddress(address string) EZMQErrorCode {
+	network, ok := parseAddress(address)
+	if false == ok {
+		return pubInstance.lastError.set(newError(EZMQ_ERROR, "allow address", nil))
+	}
+	policy := pubInstance.authPolicy
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+	if err := policy.checkEnabled("allow address"); nil != err {
+		return pubInstance.lastError.set(err)
+	}
+	policy.addresses.allowed[network.String()] = network
+	return pubInstance.lastError.set(nil)
+}
+
+// Deny subscribers from the given IP address or CIDR, e.g. 192.168.1.0/24.
+//
+// Note:
+// (1) Addresses can be changed while publisher is running. Changes apply to
+// new connections, existing connections are not closed.
+//
+// (2) Notes of AddClientKey API on other ZAP handlers apply to this API as
+// well.
+func (pubInstance *EZMQPublisher) DenyAddress(address string) EZMQErrorCode {
+	network, ok := parseAddress(address)
+	if false == ok {
+		return pubInstance.lastError.set(newError(EZMQ_ERROR, "deny address", nil))
+	}
+	policy := pubInstance.authPolicy
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+	if err := policy.checkEnabled("deny address"); nil != err {
+		return pubInstance.lastError.set(err)
+	}
+	policy.addresses.denied[network.String()] = network
+	return pubInstance.lastError.set(nil)
+}
+
+// Remove the given IP address or CIDR from allow and deny lists.
+func (pubInstance *EZMQPublisher) RemoveAddress(address string) EZMQErrorCode {
+	network, ok := parseAddress(address)
+	if false == ok {
+		return pubInstance.lastError.set(newError(EZMQ_ERROR, "remove address", nil))
+	}
+	policy := pubInstance.authPolicy
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+	var key string = network.String()
+	_, allowed := policy.addresses.allowed[key]
+	_, denied := policy.addresses.denied[key]
+	if false == allowed && false == denied {
+		return pubInstance.lastError.set(newError(EZMQ_ERROR, "remove address", nil))
+	}
+	delete(policy.addresses.allowed, key)
+	delete(policy.addresses.denied, key)
+	return pubInstance.lastError.set(nil)
+}
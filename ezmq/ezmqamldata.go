@@ -0,0 +1,77 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+var errEmptyAMLData = errors.New("AML data is not set")
+
+// Structure represents EZMQAMLData.
+//
+// AMLData holds an AutomationML (CAEX) document serialized as XML.
+type EZMQAMLData struct {
+	AMLData []byte
+}
+
+// Get AML data
+func (dataInstance *EZMQAMLData) GetAMLData() []byte {
+	return dataInstance.AMLData
+}
+
+// Set AML data. Data should be a well-formed XML document.
+func (dataInstance *EZMQAMLData) SetAMLData(amlData []byte) EZMQErrorCode {
+	if nil == amlData || false == isWellFormedXML(amlData) {
+		return EZMQ_ERROR
+	}
+	dataInstance.AMLData = amlData
+	return EZMQ_OK
+}
+
+// Get Content type
+func (dataInstance EZMQAMLData) GetContentType() EZMQContentType {
+	return EZMQ_CONTENT_TYPE_AML
+}
+
+func (dataInstance EZMQAMLData) marshal() ([]byte, error) {
+	if 0 == len(dataInstance.AMLData) {
+		return nil, errEmptyAMLData
+	}
+	return dataInstance.AMLData, nil
+}
+
+func isWellFormedXML(data []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var hasRoot bool = false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return hasRoot
+		}
+		if nil != err {
+			return false
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			hasRoot = true
+		}
+	}
+}
@@ -22,65 +22,134 @@ import (
 	zmq "github.com/pebbe/zmq4"
 
 	"math/rand"
+	"sync"
 	"time"
 )
 
-// Structure represents EZMQAPI
+// Structure represents EZMQAPI. Each EZMQAPI has its own zmq context and
+// defaults for the publishers and subscribers created with it.
 type EZMQAPI struct {
-	context *zmq.Context
-	status  EZMQStatusCode
+	context       *zmq.Context
+	status        EZMQStatusCode
+	ioThreads     int
+	headerVersion int
+	mutex         sync.Mutex
+	lastError     errorHolder
 }
 
 var instance *EZMQAPI
+var instanceOnce sync.Once
+var seedOnce sync.Once
 
-// Get EZMQAPI instance.
+// Get EZMQAPI instance which is shared across the process.
 func GetInstance() *EZMQAPI {
-	if nil == instance {
-		instance = &EZMQAPI{}
-		instance.status = EZMQ_Constructed
-		InitLogger()
+	instanceOnce.Do(func() {
+		instance = GetEZMQAPI()
+	})
+	return instance
+}
+
+// Constructs an independent EZMQAPI instance.
+//
+// Note:
+// (1) Initialize() should be called on it before use. Publishers and
+// subscribers are created on it using WithAPI option.
+func GetEZMQAPI() *EZMQAPI {
+	var ezmqInstance *EZMQAPI
+	ezmqInstance = &EZMQAPI{}
+	ezmqInstance.status = EZMQ_Constructed
+	ezmqInstance.ioThreads = 1
+	ezmqInstance.headerVersion = EZMQ_HEADER_VERSION
+	InitLogger()
+	seedOnce.Do(func() {
 		rand.Seed(time.Now().UnixNano())
+	})
+	return ezmqInstance
+}
+
+// Set number of IO threads of zmq context. Default is 1.
+//
+// Note:
+// (1) This API should be called before Initialize() API.
+func (ezmqInstance *EZMQAPI) SetIoThreads(count int) EZMQErrorCode {
+	if count < 0 {
+		return ezmqInstance.lastError.set(newError(EZMQ_ERROR, "set io threads", nil))
 	}
-	return instance
+	ezmqInstance.mutex.Lock()
+	defer ezmqInstance.mutex.Unlock()
+	ezmqInstance.ioThreads = count
+	return ezmqInstance.lastError.set(nil)
+}
+
+// Set default header version of publishers created on this instance.
+func (ezmqInstance *EZMQAPI) SetHeaderVersion(version int) EZMQErrorCode {
+	if EZMQ_HEADER_VERSION_1 != version && EZMQ_HEADER_VERSION_2 != version {
+		return ezmqInstance.lastError.set(newError(EZMQ_INVALID_VERSION, "set header version", nil))
+	}
+	ezmqInstance.mutex.Lock()
+	defer ezmqInstance.mutex.Unlock()
+	ezmqInstance.headerVersion = version
+	return ezmqInstance.lastError.set(nil)
 }
 
 // Initialize required EZMQ components. This API should be called first,
 // before using any EZMQ APIs.
 func (ezmqInstance *EZMQAPI) Initialize() EZMQErrorCode {
+	ezmqInstance.mutex.Lock()
+	defer ezmqInstance.mutex.Unlock()
 	if nil == ezmqInstance.context {
 		var err error
 		ezmqInstance.context, err = zmq.NewContext()
 		if err != nil {
-			logger.Error("EZMQ initialization failed")
-			return EZMQ_ERROR
+			return ezmqInstance.lastError.set(newError(EZMQ_SOCKET_ERROR, "initialize", err))
+		}
+		err = ezmqInstance.context.SetIoThreads(ezmqInstance.ioThreads)
+		if err != nil {
+			ezmqInstance.context.Term()
+			ezmqInstance.context = nil
+			return ezmqInstance.lastError.set(newError(EZMQ_SOCKET_ERROR, "initialize", err))
 		}
-		zmq.SetIoThreads(1)
 	}
 	logger.Debug("EZMQ initialized")
 
 	ezmqInstance.status = EZMQ_Initialized
-	return EZMQ_OK
+	return ezmqInstance.lastError.set(nil)
 }
 
 // Perform cleanup of EZMQ components.
+//
+// Note:
+// (1) Publishers and subscribers created on this instance should be stopped
+// before calling this API. It does not affect other EZMQAPI instances.
 func (ezmqInstance *EZMQAPI) Terminate() EZMQErrorCode {
+	ezmqInstance.mutex.Lock()
+	defer ezmqInstance.mutex.Unlock()
 	if ezmqInstance.context != nil {
 		err := ezmqInstance.context.Term()
 		if nil != err {
-			logger.Error("EZMQ termination failed")
-			return EZMQ_ERROR
+			return ezmqInstance.lastError.set(newError(EZMQ_SOCKET_ERROR, "terminate", err))
 		}
 		ezmqInstance.context = nil
 		logger.Debug("EZMQ terminated")
 	}
 	ezmqInstance.status = EZMQ_Terminated
-	return EZMQ_OK
+	return ezmqInstance.lastError.set(nil)
 }
 
 func (ezmqInstance *EZMQAPI) GetStatus() EZMQStatusCode {
+	ezmqInstance.mutex.Lock()
+	defer ezmqInstance.mutex.Unlock()
 	return ezmqInstance.status
 }
 
 func (ezmqInstance *EZMQAPI) GetContext() *zmq.Context {
+	ezmqInstance.mutex.Lock()
+	defer ezmqInstance.mutex.Unlock()
 	return ezmqInstance.context
 }
+
+func (ezmqInstance *EZMQAPI) getHeaderVersion() int {
+	ezmqInstance.mutex.Lock()
+	defer ezmqInstance.mutex.Unlock()
+	return ezmqInstance.headerVersion
+}
@@ -0,0 +1,236 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+	"go.uber.org/zap"
+
+	"sync"
+)
+
+// Command to terminate the broker proxy.
+const BROKER_TERMINATE = "TERMINATE"
+
+// Structure represents EZMQBroker. Publishers connect to the frontend of
+// broker and subscribers to its backend, so that services only need to know
+// the broker address. Subscriptions of subscribers are forwarded to
+// publishers.
+type EZMQBroker struct {
+	frontendEndpoint        *EZMQEndpoint
+	backendEndpoint         *EZMQEndpoint
+	frontendServerPublicKey []byte
+	frontendClientPublicKey []byte
+	frontendClientSecretKey []byte
+	backendSecretKey        []byte
+	backendSecured          bool
+	options                 []socketOption
+	lastError               errorHolder
+	mutex                   *sync.Mutex
+
+	context       *zmq.Context
+	frontend      *zmq.Socket
+	backend       *zmq.Socket
+	controlServer *zmq.Socket
+	controlClient *zmq.Socket
+	proxyDone     chan error
+}
+
+// Constructs EZMQBroker which binds frontend endpoint for publishers and
+// backend endpoint for subscribers.
+//
+// Note:
+// (1) Publishers should be created with WithReverseTopology option to connect
+// to the frontend.
+//
+// (2) Broker is created on the EZMQAPI instance given by WithAPI option,
+// otherwise on the instance returned by GetInstance().
+func GetEZMQBroker(frontend *EZMQEndpoint, backend *EZMQEndpoint, options ...EZMQOption) *EZMQBroker {
+	if nil == frontend || nil == backend {
+		return nil
+	}
+	var instance *EZMQBroker
+	var config *ezmqOptions = getOptions(options)
+	instance = &EZMQBroker{}
+	instance.frontendEndpoint = frontend
+	instance.backendEndpoint = backend
+	instance.context = config.api.GetContext()
+	InitLogger()
+	if nil == instance.context {
+		logger.Error("Context is null")
+		return nil
+	}
+	instance.options = config.socketOptions
+	instance.mutex = &sync.Mutex{}
+	return instance
+}
+
+// Starts the broker.
+func (brokerInstance *EZMQBroker) Start() EZMQErrorCode {
+	return brokerInstance.lastError.set(brokerInstance.start())
+}
+
+func (brokerInstance *EZMQBroker) start() error {
+	if nil == brokerInstance.context {
+		return newError(EZMQ_NOT_INITIALIZED, "start broker", nil)
+	}
+
+	brokerInstance.mutex.Lock()
+	defer brokerInstance.mutex.Unlock()
+	if nil != brokerInstance.frontend {
+		return nil
+	}
+	err := brokerInstance.openSockets()
+	if nil != err {
+		brokerInstance.closeSockets()
+		return err
+	}
+
+	frontend := brokerInstance.frontend
+	backend := brokerInstance.backend
+	controlServer := brokerInstance.controlServer
+	// keys are applied on sockets, they are not needed anymore
+	brokerInstance.wipeKeys()
+	proxyDone := make(chan error, 1)
+	brokerInstance.proxyDone = proxyDone
+	go func() {
+		proxyDone <- zmq.ProxySteerable(frontend, backend, nil, controlServer)
+	}()
+	logger.Debug("Broker started", zap.String("frontend", brokerInstance.frontendEndpoint.String()),
+		zap.String("backend", brokerInstance.backendEndpoint.String()))
+	return nil
+}
+
+func (brokerInstance *EZMQBroker) openSockets() error {
+	var err error
+	brokerInstance.frontend, err = brokerInstance.context.NewSocket(zmq.XSUB)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "create broker frontend socket", err)
+	}
+	err = applySocketOptions(brokerInstance.frontend, brokerInstance.options)
+	if nil == err {
+		err = setEndpointOptions(brokerInstance.frontend, brokerInstance.frontendEndpoint)
+	}
+	if nil == err {
+		err = brokerInstance.setFrontendSecurity()
+	}
+	if nil == err {
+		err = attachSocket(brokerInstance.frontend, brokerInstance.frontendEndpoint, true, "broker frontend")
+	}
+	if nil != err {
+		return err
+	}
+
+	brokerInstance.backend, err = brokerInstance.context.NewSocket(zmq.XPUB)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "create broker backend socket", err)
+	}
+	err = applySocketOptions(brokerInstance.backend, brokerInstance.options)
+	if nil == err {
+		err = setEndpointOptions(brokerInstance.backend, brokerInstance.backendEndpoint)
+	}
+	if nil == err {
+		err = brokerInstance.setBackendSecurity()
+	}
+	if nil == err {
+		err = attachSocket(brokerInstance.backend, brokerInstance.backendEndpoint, true, "broker backend")
+	}
+	if nil != err {
+		return err
+	}
+
+	// control sockets to terminate the proxy
+	var address = getInProcUniqueAddress()
+	brokerInstance.controlServer, err = brokerInstance.context.NewSocket(zmq.PAIR)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "create broker control socket", err)
+	}
+	err = brokerInstance.controlServer.Bind(address)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "bind broker control socket", err)
+	}
+	brokerInstance.controlClient, err = brokerInstance.context.NewSocket(zmq.PAIR)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "create broker control socket", err)
+	}
+	err = brokerInstance.controlClient.Connect(address)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "connect broker control socket", err)
+	}
+	return nil
+}
+
+func (brokerInstance *EZMQBroker) closeSockets() {
+	for _, socket := range []*zmq.Socket{brokerInstance.frontend, brokerInstance.backend,
+		brokerInstance.controlServer, brokerInstance.controlClient} {
+		if nil != socket {
+			socket.Close()
+		}
+	}
+	brokerInstance.frontend = nil
+	brokerInstance.backend = nil
+	brokerInstance.controlServer = nil
+	brokerInstance.controlClient = nil
+}
+
+// Stops the broker.
+func (brokerInstance *EZMQBroker) Stop() EZMQErrorCode {
+	return brokerInstance.lastError.set(brokerInstance.stop())
+}
+
+func (brokerInstance *EZMQBroker) stop() error {
+	brokerInstance.mutex.Lock()
+	defer brokerInstance.mutex.Unlock()
+	if nil == brokerInstance.frontend {
+		return newError(EZMQ_NOT_STARTED, "stop broker", nil)
+	}
+
+	// sockets can be closed only once proxy is terminated
+	_, err := brokerInstance.controlClient.Send(BROKER_TERMINATE, 0)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "terminate broker", err)
+	}
+	err = <-brokerInstance.proxyDone
+	if nil != err {
+		logger.Debug("Broker proxy terminated", zap.Error(err))
+	}
+	brokerInstance.closeSockets()
+	brokerInstance.proxyDone = nil
+	brokerInstance.wipeKeys()
+	logger.Debug("Broker stopped")
+	return nil
+}
+
+// Get frontend endpoint to which publishers connect.
+func (brokerInstance *EZMQBroker) GetFrontendEndpoint() *EZMQEndpoint {
+	return brokerInstance.frontendEndpoint
+}
+
+// Get backend endpoint to which subscribers connect.
+func (brokerInstance *EZMQBroker) GetBackendEndpoint() *EZMQEndpoint {
+	return brokerInstance.backendEndpoint
+}
+
+// Get error of the most recent API call on this broker, nil if it succeeded.
+//
+// Note:
+// (1) Error is kept per broker, not per go routine. If APIs are called
+// concurrently, it is the error of the call which completed last.
+func (brokerInstance *EZMQBroker) GetLastError() error {
+	return brokerInstance.lastError.get()
+}
@@ -0,0 +1,105 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+// Set the keys used by broker frontend to connect to secured publishers.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+//
+// (2) Frontend acts as a CURVE client of publishers which hold the server
+// private key.
+//
+// (3) This API should be called before Start() API.
+//
+// (4) Keys are copied and the copy of private key is wiped once broker is
+// started, and on Stop() and Close() APIs.
+func (brokerInstance *EZMQBroker) SetFrontendKeys(serverPublicKey []byte, clientPrivateKey []byte,
+	clientPublicKey []byte) EZMQErrorCode {
+	err := checkCurve("set broker frontend keys")
+	if nil != err {
+		return brokerInstance.lastError.set(err)
+	}
+	if false == isValidKey(serverPublicKey) || false == isValidKey(clientPrivateKey) ||
+		false == isValidKey(clientPublicKey) {
+		return brokerInstance.lastError.set(newError(EZMQ_KEY_INVALID, "set broker frontend keys", nil))
+	}
+	brokerInstance.mutex.Lock()
+	defer brokerInstance.mutex.Unlock()
+	wipe(brokerInstance.frontendClientSecretKey)
+	brokerInstance.frontendServerPublicKey = copyKey(serverPublicKey)
+	brokerInstance.frontendClientSecretKey = copyKey(clientPrivateKey)
+	brokerInstance.frontendClientPublicKey = copyKey(clientPublicKey)
+	return brokerInstance.lastError.set(nil)
+}
+
+// Set the server private/secret key used by broker backend towards subscribers.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+//
+// (2) This API should be called before Start() API.
+//
+// (3) Key is copied and the copy is wiped once broker is started, and on
+// Stop() and Close() APIs.
+func (brokerInstance *EZMQBroker) SetBackendPrivateKey(key []byte) EZMQErrorCode {
+	err := checkCurve("set broker backend key")
+	if nil != err {
+		return brokerInstance.lastError.set(err)
+	}
+	if false == isValidKey(key) {
+		return brokerInstance.lastError.set(newError(EZMQ_KEY_INVALID, "set broker backend key", nil))
+	}
+	brokerInstance.mutex.Lock()
+	defer brokerInstance.mutex.Unlock()
+	wipe(brokerInstance.backendSecretKey)
+	brokerInstance.backendSecretKey = copyKey(key)
+	brokerInstance.backendSecured = true
+	return brokerInstance.lastError.set(nil)
+}
+
+func (brokerInstance *EZMQBroker) setFrontendSecurity() error {
+	if len(brokerInstance.frontendServerPublicKey) != SUB_KEY_LENGTH {
+		return nil
+	}
+	// key is wiped after previous start
+	if len(brokerInstance.frontendClientSecretKey) != SUB_KEY_LENGTH {
+		return newError(EZMQ_KEY_INVALID, "set broker frontend keys", nil)
+	}
+	err := brokerInstance.frontend.ClientAuthCurve(string(brokerInstance.frontendServerPublicKey[:]),
+		string(brokerInstance.frontendClientPublicKey[:]), string(brokerInstance.frontendClientSecretKey[:]))
+	if nil != err {
+		return newError(EZMQ_KEY_INVALID, "set broker frontend keys", err)
+	}
+	return nil
+}
+
+func (brokerInstance *EZMQBroker) setBackendSecurity() error {
+	if false == brokerInstance.backendSecured {
+		return nil
+	}
+	// key is wiped after previous start
+	if len(brokerInstance.backendSecretKey) != PUB_KEY_LENGTH {
+		return newError(EZMQ_KEY_INVALID, "set broker backend key", nil)
+	}
+	err := brokerInstance.backend.ServerAuthCurve("", string(brokerInstance.backendSecretKey[:]))
+	if nil != err {
+		return newError(EZMQ_KEY_INVALID, "set broker backend key", err)
+	}
+	return nil
+}
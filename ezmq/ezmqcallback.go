@@ -0,0 +1,110 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"context"
+)
+
+// Publish message and report socket errors to error callback.
+func (pubInstance *EZMQPublisher) publishInternal(ctx context.Context, topic string, ezmqMsg EZMQMessage) error {
+	err := pubInstance.publishMessage(ctx, topic, ezmqMsg)
+	if EZMQ_SOCKET_ERROR == GetErrorCode(err) && nil != pubInstance.errorCallback {
+		pubInstance.errorCallback(EZMQ_SOCKET_ERROR)
+	}
+	return err
+}
+
+// Start callback is called if socket is created or start failed, not for
+// start of a running instance.
+func (pubInstance *EZMQPublisher) notifyStart(created bool, code EZMQErrorCode) EZMQErrorCode {
+	if nil != pubInstance.startCallback && (true == created || EZMQ_OK != code) {
+		pubInstance.startCallback(code)
+	}
+	return code
+}
+
+func (pubInstance *EZMQPublisher) notifyStartError(created bool, err error) error {
+	pubInstance.notifyStart(created, GetErrorCode(err))
+	return err
+}
+
+func (pubInstance *EZMQPublisher) notifyStop(code EZMQErrorCode) EZMQErrorCode {
+	if nil != pubInstance.stopCallback {
+		pubInstance.stopCallback(code)
+	}
+	return code
+}
+
+func (pubInstance *EZMQPublisher) notifyStopError(err error) error {
+	pubInstance.notifyStop(GetErrorCode(err))
+	return err
+}
+
+// Set callbacks for start, stop and asynchronous errors of subscriber.
+//
+// Note:
+// (1) Start and stop callbacks are called with result of start and stop APIs.
+// Start callback is not called when Start() is called on a running
+// subscriber.
+//
+// (2) Error callback is called for socket errors reported by ZeroMQ after
+// start, e.g. authentication failure with EZMQ_KEY_INVALID.
+//
+// (3) Messages with unknown EZMQ header version are dropped and reported to
+// error callback with EZMQ_INVALID_VERSION.
+//
+// (4) This API should be called before Start() API.
+func (subInstance *EZMQSubscriber) SetStatusCallbacks(startCallback EZMQStartCB, stopCallback EZMQStopCB,
+	errorCallback EZMQErrorCB) {
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	subInstance.startCallback = startCallback
+	subInstance.stopCallback = stopCallback
+	subInstance.errorCallback = errorCallback
+}
+
+func (subInstance *EZMQSubscriber) notifyStart(created bool, code EZMQErrorCode) EZMQErrorCode {
+	subInstance.mutex.Lock()
+	startCallback := subInstance.startCallback
+	subInstance.mutex.Unlock()
+	if nil != startCallback && (true == created || EZMQ_OK != code) {
+		startCallback(code)
+	}
+	return code
+}
+
+func (subInstance *EZMQSubscriber) notifyStartError(created bool, err error) error {
+	subInstance.notifyStart(created, GetErrorCode(err))
+	return err
+}
+
+func (subInstance *EZMQSubscriber) notifyStop(code EZMQErrorCode) EZMQErrorCode {
+	subInstance.mutex.Lock()
+	stopCallback := subInstance.stopCallback
+	subInstance.mutex.Unlock()
+	if nil != stopCallback {
+		stopCallback(code)
+	}
+	return code
+}
+
+func (subInstance *EZMQSubscriber) notifyStopError(err error) error {
+	subInstance.notifyStop(GetErrorCode(err))
+	return err
+}
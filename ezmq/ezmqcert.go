@@ -0,0 +1,348 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+	"go.uber.org/zap"
+
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Suffix of the secret certificate file, same as czmq.
+const SECRET_CERT_SUFFIX = "_secret"
+
+// Characters allowed in ZPL names other than letters and digits.
+const zplNameChars = "$-_@.&+/"
+
+// CURVE certificate holding a key pair and metadata. Certificates are stored
+// in ZPL format compatible with czmq zcert.
+//
+// Note:
+// (1) Certificate loaded from a public certificate file has no secret key.
+type EZMQCertificate struct {
+	publicKey []byte
+	secretKey []byte
+	metadata  map[string]string
+}
+
+// Generate a new CURVE key pair. Keys are 40-character strings encoded in the
+// Z85 encoding format.
+//
+// Note:
+// (1) ZeroMQ binding generates keys as Go strings which can not be wiped.
+// Returned secret key should be wiped by application after use.
+func GenerateKeyPair() ([]byte, []byte, EZMQErrorCode) {
+	publicKey, secretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		logger.Error("Generate key pair failed", zap.Error(err))
+		return nil, nil, EZMQ_ERROR
+	}
+	return []byte(publicKey), []byte(secretKey), EZMQ_OK
+}
+
+// Derive public key from the given secret key.
+//
+// Note:
+// (1) Secret key is passed to ZeroMQ binding as a temporary Go string, same as
+// when keys are applied on sockets.
+func GetPublicKey(secretKey []byte) ([]byte, EZMQErrorCode) {
+	if false == isValidKey(secretKey) {
+		return nil, EZMQ_KEY_INVALID
+	}
+	publicKey, err := zmq.AuthCurvePublic(string(secretKey))
+	if nil != err {
+		logger.Error("Derive public key failed", zap.Error(err))
+		return nil, EZMQ_ERROR
+	}
+	return []byte(publicKey), EZMQ_OK
+}
+
+// Create certificate with a new CURVE key pair.
+func GetEZMQCertificate() (*EZMQCertificate, EZMQErrorCode) {
+	publicKey, secretKey, result := GenerateKeyPair()
+	if result != EZMQ_OK {
+		return nil, result
+	}
+	return newCertificate(publicKey, secretKey), EZMQ_OK
+}
+
+// Create certificate from the given secret key. Public key is derived from
+// secret key.
+func GetEZMQCertificateFromKey(secretKey []byte) (*EZMQCertificate, EZMQErrorCode) {
+	publicKey, result := GetPublicKey(secretKey)
+	if result != EZMQ_OK {
+		return nil, result
+	}
+	return newCertificate(publicKey, copyKey(secretKey)), EZMQ_OK
+}
+
+// Load certificate from file. Secret certificate, <filename>_secret is tried
+// first, then the public certificate, <filename>.
+func LoadEZMQCertificate(filename string) (*EZMQCertificate, EZMQErrorCode) {
+	data, err := ioutil.ReadFile(filename + SECRET_CERT_SUFFIX)
+	if nil != err {
+		data, err = ioutil.ReadFile(filename)
+	}
+	if nil != err {
+		logger.Error("Read certificate failed", zap.Error(err))
+		return nil, EZMQ_ERROR
+	}
+	defer wipe(data)
+	values, ok := parseZPL(data)
+	if false == ok {
+		return nil, EZMQ_KEY_INVALID
+	}
+	cert := newCertificate(copyKey(values["curve/public-key"]), nil)
+	if false == isValidKey(cert.publicKey) {
+		return nil, EZMQ_KEY_INVALID
+	}
+	if secretKey, exists := values["curve/secret-key"]; exists {
+		cert.secretKey = copyKey(secretKey)
+		if false == isValidKey(cert.secretKey) {
+			cert.Close()
+			return nil, EZMQ_KEY_INVALID
+		}
+	}
+	for name, value := range values {
+		if strings.HasPrefix(name, "metadata/") {
+			cert.metadata[strings.TrimPrefix(name, "metadata/")] = string(value)
+		}
+	}
+	return cert, EZMQ_OK
+}
+
+func newCertificate(publicKey []byte, secretKey []byte) *EZMQCertificate {
+	cert := &EZMQCertificate{}
+	cert.publicKey = publicKey
+	cert.secretKey = secretKey
+	cert.metadata = make(map[string]string)
+	return cert
+}
+
+// Get public key of certificate.
+func (cert *EZMQCertificate) GetPublicKey() []byte {
+	return cert.publicKey
+}
+
+// Get secret key of certificate. Returns nil for public certificate.
+//
+// Note:
+// (1) Returned key is owned by certificate, it is wiped by Close() API.
+func (cert *EZMQCertificate) GetSecretKey() []byte {
+	return cert.secretKey
+}
+
+// Set metadata of certificate.
+//
+// Note:
+// (1) Name can have letters [a-z, A-Z], numerics [0-9] and special characters
+// $ - _ @ . & + and /
+//
+// (2) ZPL has no escape sequences, so value can not have line breaks or other
+// control characters, and can not have both single and double quotes.
+func (cert *EZMQCertificate) SetMeta(name string, value string) EZMQErrorCode {
+	if false == isValidZPLName(name) {
+		return EZMQ_ERROR
+	}
+	if _, ok := quoteZPL(value); false == ok {
+		return EZMQ_ERROR
+	}
+	cert.metadata[name] = value
+	return EZMQ_OK
+}
+
+// Get metadata of certificate. Returns empty string if not present.
+func (cert *EZMQCertificate) GetMeta(name string) string {
+	return cert.metadata[name]
+}
+
+// Save public certificate to <filename> and secret certificate to
+// <filename>_secret.
+//
+// Note:
+// (1) Secret certificate file is readable only by the owner.
+func (cert *EZMQCertificate) Save(filename string) EZMQErrorCode {
+	result := cert.SavePublic(filename)
+	if result != EZMQ_OK {
+		return result
+	}
+	return cert.SaveSecret(filename + SECRET_CERT_SUFFIX)
+}
+
+// Save public certificate to the given file.
+func (cert *EZMQCertificate) SavePublic(filename string) EZMQErrorCode {
+	header := "#   ZeroMQ CURVE Public Certificate\n" +
+		"#   Exchange securely, or use a secure mechanism to verify the contents\n" +
+		"#   of this file after exchange.\n"
+	return cert.write(filename, header, false, 0644)
+}
+
+// Save secret certificate to the given file.
+func (cert *EZMQCertificate) SaveSecret(filename string) EZMQErrorCode {
+	if nil == cert.secretKey {
+		return EZMQ_KEY_INVALID
+	}
+	header := "#   ZeroMQ CURVE **Secret** Certificate\n" +
+		"#   DO NOT PROVIDE THIS FILE TO OTHER USERS nor change its permissions.\n"
+	return cert.write(filename, header, true, 0600)
+}
+
+func (cert *EZMQCertificate) write(filename string, header string, secret bool, mode uint32) EZMQErrorCode {
+	var buffer bytes.Buffer
+	buffer.WriteString("#   ****  Generated on " + time.Now().Format("2006-01-02 15:04:05") + " by ezmq  ****\n")
+	buffer.WriteString(header + "\n")
+	buffer.WriteString("metadata\n")
+	// names are sorted, so that saving same certificate gives same content
+	names := make([]string, 0, len(cert.metadata))
+	for name := range cert.metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := cert.metadata[name]
+		quoted, ok := quoteZPL(value)
+		if false == isValidZPLName(name) || false == ok {
+			logger.Error("Invalid certificate metadata", zap.String("name", name))
+			return EZMQ_ERROR
+		}
+		buffer.WriteString("    " + name + " = " + quoted + "\n")
+	}
+	buffer.WriteString("curve\n")
+	buffer.WriteString("    public-key = \"" + string(cert.publicKey) + "\"\n")
+	if true == secret {
+		// grow before writing, so that secret key is not left in a reallocated buffer
+		buffer.Grow(len(cert.secretKey) + 32)
+		buffer.WriteString("    secret-key = \"")
+		buffer.Write(cert.secretKey)
+		buffer.WriteString("\"\n")
+	}
+	defer wipe(buffer.Bytes())
+	if err := writeFileAtomic(filename, buffer.Bytes(), os.FileMode(mode)); nil != err {
+		logger.Error("Write certificate failed", zap.Error(err))
+		return EZMQ_ERROR
+	}
+	return EZMQ_OK
+}
+
+// Write data to a temporary file with the given permissions in the directory
+// of filename and rename it over filename. Existing file is replaced as a
+// whole, so it neither keeps its permissions nor is left partially written.
+func writeFileAtomic(filename string, data []byte, mode os.FileMode) error {
+	// temporary file is created readable only by the owner
+	file, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".tmp")
+	if nil != err {
+		return err
+	}
+	tempName := file.Name()
+	if err = file.Chmod(mode); nil == err {
+		_, err = file.Write(data)
+	}
+	if nil == err {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if nil == err {
+		err = closeErr
+	}
+	if nil == err {
+		err = os.Rename(tempName, filename)
+	}
+	if nil != err {
+		os.Remove(tempName)
+	}
+	return err
+}
+
+func isValidZPLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, char := range name {
+		if (char < 'a' || char > 'z') && (char < 'A' || char > 'Z') && (char < '0' || char > '9') &&
+			false == strings.ContainsRune(zplNameChars, char) {
+			return false
+		}
+	}
+	return true
+}
+
+// Quote value for ZPL. Value is enclosed in double quotes, or in single quotes
+// if it has double quotes.
+func quoteZPL(value string) (string, bool) {
+	for _, char := range value {
+		if char < ' ' || char == 0x7F {
+			return "", false
+		}
+	}
+	if false == strings.Contains(value, "\"") {
+		return "\"" + value + "\"", true
+	}
+	if false == strings.Contains(value, "'") {
+		return "'" + value + "'", true
+	}
+	return "", false
+}
+
+// Parse ZPL data into values keyed by their path, e.g. "curve/public-key".
+// Values are slices of data, so that secret key is not copied. Only the subset
+// of ZPL used by certificates is supported.
+func parseZPL(data []byte) (map[string][]byte, bool) {
+	values := make(map[string][]byte)
+	var sections []string
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimRight(line, " \t\r")
+		content := bytes.TrimLeft(line, " ")
+		if len(content) == 0 || content[0] == '#' {
+			continue
+		}
+		indent := len(line) - len(content)
+		if indent%4 != 0 || indent/4 > len(sections) {
+			return nil, false
+		}
+		sections = sections[:indent/4]
+		index := bytes.IndexByte(content, '=')
+		if index < 0 {
+			sections = append(sections, string(content))
+			continue
+		}
+		name := string(bytes.TrimSpace(content[:index]))
+		value := bytes.TrimSpace(content[index+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+			if value[len(value)-1] != value[0] {
+				return nil, false
+			}
+			value = value[1 : len(value)-1]
+		}
+		values[strings.Join(append(sections, name), "/")] = value
+	}
+	return values, true
+}
+
+// Wipe secret key of certificate. Certificate should not be used after this
+// API.
+func (cert *EZMQCertificate) Close() {
+	wipe(cert.secretKey)
+	cert.secretKey = nil
+}
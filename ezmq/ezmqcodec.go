@@ -0,0 +1,151 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	proto "github.com/golang/protobuf/proto"
+
+	"errors"
+	"sync"
+)
+
+// Serializes an ezmq message to bytes which will be published.
+type EZMQMarshalFunc func(ezmqMsg EZMQMessage) ([]byte, error)
+
+// De-serializes received bytes to an ezmq message which will be given to
+// subscriber callbacks.
+type EZMQUnmarshalFunc func(data []byte) (EZMQMessage, error)
+
+type ezmqCodec struct {
+	marshal   EZMQMarshalFunc
+	unmarshal EZMQUnmarshalFunc
+}
+
+var errInvalidMessage = errors.New("message does not match content type")
+
+var codecs = map[EZMQContentType]ezmqCodec{
+	EZMQ_CONTENT_TYPE_PROTOBUF: {marshalEvent, unmarshalEvent},
+	EZMQ_CONTENT_TYPE_BYTEDATA: {marshalByteData, unmarshalByteData},
+	EZMQ_CONTENT_TYPE_AML:      {marshalAMLData, unmarshalAMLData},
+	EZMQ_CONTENT_TYPE_JSON:     {marshalJSONData, unmarshalJSONData},
+}
+var codecMutex = &sync.RWMutex{}
+
+// Register a codec for an application-defined content type. Publisher uses
+// marshal for messages whose GetContentType() returns contentType and
+// subscriber uses unmarshal for received messages of that content type.
+//
+// Note:
+// (1) Content type should be in range [EZMQ_CONTENT_TYPE_APPLICATION, EZMQ_CONTENT_TYPE_APPLICATION_MAX].
+//
+// (2) Codec should be registered with the same content type on both publisher and subscriber side.
+func RegisterCodec(contentType EZMQContentType, marshal EZMQMarshalFunc, unmarshal EZMQUnmarshalFunc) EZMQErrorCode {
+	if false == isApplicationContentType(contentType) {
+		return EZMQ_INVALID_CONTENT_TYPE
+	}
+	if nil == marshal || nil == unmarshal {
+		return EZMQ_ERROR
+	}
+	codecMutex.Lock()
+	defer codecMutex.Unlock()
+	codecs[contentType] = ezmqCodec{marshal, unmarshal}
+	return EZMQ_OK
+}
+
+// Un-register codec of an application-defined content type.
+func UnRegisterCodec(contentType EZMQContentType) EZMQErrorCode {
+	if false == isApplicationContentType(contentType) {
+		return EZMQ_INVALID_CONTENT_TYPE
+	}
+	codecMutex.Lock()
+	defer codecMutex.Unlock()
+	if _, exists := codecs[contentType]; false == exists {
+		return EZMQ_INVALID_CONTENT_TYPE
+	}
+	delete(codecs, contentType)
+	return EZMQ_OK
+}
+
+func isApplicationContentType(contentType EZMQContentType) bool {
+	return contentType >= EZMQ_CONTENT_TYPE_APPLICATION && contentType <= EZMQ_CONTENT_TYPE_APPLICATION_MAX
+}
+
+func getCodec(contentType EZMQContentType) (ezmqCodec, bool) {
+	codecMutex.RLock()
+	defer codecMutex.RUnlock()
+	codec, exists := codecs[contentType]
+	return codec, exists
+}
+
+func marshalEvent(ezmqMsg EZMQMessage) ([]byte, error) {
+	switch event := ezmqMsg.(type) {
+	case Event:
+		return proto.Marshal(&event)
+	case *Event:
+		return proto.Marshal(event)
+	}
+	return nil, errInvalidMessage
+}
+
+func unmarshalEvent(data []byte) (EZMQMessage, error) {
+	var event Event
+	err := proto.Unmarshal(data, &event)
+	return event, err
+}
+
+func marshalByteData(ezmqMsg EZMQMessage) ([]byte, error) {
+	switch byteData := ezmqMsg.(type) {
+	case EZMQByteData:
+		return byteData.GetByteData(), nil
+	case *EZMQByteData:
+		return byteData.GetByteData(), nil
+	}
+	return nil, errInvalidMessage
+}
+
+func unmarshalByteData(data []byte) (EZMQMessage, error) {
+	return EZMQByteData{ByteData: data}, nil
+}
+
+func marshalAMLData(ezmqMsg EZMQMessage) ([]byte, error) {
+	switch amlData := ezmqMsg.(type) {
+	case EZMQAMLData:
+		return amlData.marshal()
+	case *EZMQAMLData:
+		return amlData.marshal()
+	}
+	return nil, errInvalidMessage
+}
+
+func unmarshalAMLData(data []byte) (EZMQMessage, error) {
+	return EZMQAMLData{AMLData: data}, nil
+}
+
+func marshalJSONData(ezmqMsg EZMQMessage) ([]byte, error) {
+	switch jsonData := ezmqMsg.(type) {
+	case EZMQJSONData:
+		return jsonData.marshal()
+	case *EZMQJSONData:
+		return jsonData.marshal()
+	}
+	return nil, errInvalidMessage
+}
+
+func unmarshalJSONData(data []byte) (EZMQMessage, error) {
+	return EZMQJSONData{JSONData: data}, nil
+}
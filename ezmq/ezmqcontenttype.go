@@ -23,6 +23,14 @@ type EZMQContentType int
 const (
 	EZMQ_CONTENT_TYPE_PROTOBUF = 0
 	EZMQ_CONTENT_TYPE_BYTEDATA = 1
-	EZMQ_CONTENT_TYPE_AML      = 2 // Not in use as of now
-	EZMQ_CONTENT_TYPE_JSON     = 3 // Not in use as of now
+	EZMQ_CONTENT_TYPE_AML      = 2
+	EZMQ_CONTENT_TYPE_JSON     = 3
+
+	// Reserved: header value indicating that an application-defined content
+	// type follows the header byte. Not to be used as a content type.
+	EZMQ_CONTENT_TYPE_EXTENDED = 7
+
+	// Range of application-defined content types. See RegisterCodec.
+	EZMQ_CONTENT_TYPE_APPLICATION     = 8
+	EZMQ_CONTENT_TYPE_APPLICATION_MAX = 0xFFFF
 )
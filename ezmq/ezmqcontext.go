@@ -0,0 +1,154 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+
+	List "container/list"
+	"context"
+	"time"
+)
+
+// Interval at which context aware APIs check for cancellation while waiting
+// on sockets.
+const CONTEXT_POLL_INTERVAL = 100 * time.Millisecond
+
+// Starts PUB instance.
+//
+// Note:
+// (1) If ctx is already done, publisher is not started and error with
+// EZMQ_CANCELED code wrapping ctx.Err() is returned.
+//
+// (2) In reverse topology, if ctx can be canceled or has a deadline, this API
+// waits till publisher is connected to subscriber. If ctx is done before that,
+// publisher is stopped and error with EZMQ_CANCELED code is returned.
+func (pubInstance *EZMQPublisher) StartContext(ctx context.Context) error {
+	if nil != ctx.Err() {
+		return pubInstance.notifyStartError(false, pubInstance.lastError.record(newContextError("start publisher", ctx.Err())))
+	}
+	monitor, created, err := pubInstance.start(nil != ctx.Done())
+	if nil != err || nil == monitor {
+		return pubInstance.notifyStartError(created, pubInstance.lastError.record(err))
+	}
+
+	err = monitor.waitConnected(ctx)
+	if nil != err {
+		// ctx is already done, so stop is bounded same as Stop() API
+		stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		pubInstance.stop(stopCtx)
+		cancel()
+	}
+	return pubInstance.notifyStartError(created, pubInstance.lastError.record(err))
+}
+
+// Publish events on the socket for subscribers.
+//
+// Note:
+// (1) Deadline of ctx [if any] is used as send timeout.
+func (pubInstance *EZMQPublisher) PublishContext(ctx context.Context, ezmqMsg EZMQMessage) error {
+	return pubInstance.lastError.record(pubInstance.publishInternal(ctx, "", ezmqMsg))
+}
+
+// Publish events on a specific topic on socket for subscribers.
+//
+// Note:
+// (1) Deadline of ctx [if any] is used as send timeout.
+func (pubInstance *EZMQPublisher) PublishOnTopicContext(ctx context.Context, topic string, ezmqMsg EZMQMessage) error {
+	return pubInstance.lastError.record(pubInstance.publishOnTopic(ctx, topic, ezmqMsg))
+}
+
+// Publish an events on list of topics on socket for subscribers.
+//
+// Note:
+// (1) Deadline of ctx [if any] is used as send timeout for each topic.
+func (pubInstance *EZMQPublisher) PublishOnTopicListContext(ctx context.Context, topicList List.List, ezmqMsg EZMQMessage) error {
+	return pubInstance.lastError.record(pubInstance.publishOnTopicList(ctx, topicList, ezmqMsg))
+}
+
+// Stops PUB instance.
+//
+// Note:
+// (1) Pending messages are kept till deadline of ctx [if any] and dropped
+// after that.
+//
+// (2) Publisher is stopped even if ctx is already done.
+func (pubInstance *EZMQPublisher) StopContext(ctx context.Context) error {
+	return pubInstance.notifyStopError(pubInstance.lastError.record(pubInstance.stop(ctx)))
+}
+
+// Starts SUB instance.
+//
+// Note:
+// (1) If ctx can be canceled or has a deadline, this API waits till
+// subscriber is connected to publisher. If ctx is done before that,
+// subscriber is stopped and error with EZMQ_CANCELED code wrapping ctx.Err()
+// is returned.
+func (subInstance *EZMQSubscriber) StartContext(ctx context.Context) error {
+	if nil != ctx.Err() {
+		return subInstance.notifyStartError(false, subInstance.lastError.record(newContextError("start subscriber", ctx.Err())))
+	}
+	monitor, created, err := subInstance.start(nil != ctx.Done())
+	if nil != err || nil == monitor {
+		return subInstance.notifyStartError(created, subInstance.lastError.record(err))
+	}
+
+	err = monitor.waitConnected(ctx)
+	if nil != err {
+		// ctx is already done, so stop is bounded same as Stop() API
+		stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		subInstance.stop(stopCtx)
+		cancel()
+	}
+	return subInstance.notifyStartError(created, subInstance.lastError.record(err))
+}
+
+// Stops SUB instance.
+//
+// Note:
+// (1) Waits for receiver routine to stop till ctx is done. Subscriber is
+// stopped even if ctx is already done.
+func (subInstance *EZMQSubscriber) StopContext(ctx context.Context) error {
+	return subInstance.notifyStopError(subInstance.lastError.record(subInstance.stop(ctx)))
+}
+
+func newContextError(op string, err error) *EZMQError {
+	return newError(EZMQ_CANCELED, op, err)
+}
+
+// Error for a failed send, with EZMQ_CANCELED code if send timed out as per
+// deadline of ctx.
+func newSendError(ctx context.Context, op string, err error) *EZMQError {
+	if _, exists := ctx.Deadline(); true == exists && zmq.EAGAIN == zmq.AsErrno(err) {
+		return newContextError(op, context.DeadlineExceeded)
+	}
+	return newError(EZMQ_SOCKET_ERROR, op, err)
+}
+
+// Get time left till deadline of ctx [if any].
+func getTimeout(ctx context.Context) (time.Duration, bool) {
+	deadline, exists := ctx.Deadline()
+	if false == exists {
+		return 0, false
+	}
+	timeout := time.Until(deadline)
+	if timeout < 0 {
+		timeout = 0
+	}
+	return timeout, true
+}
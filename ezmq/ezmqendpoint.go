@@ -0,0 +1,168 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Transports supported by EZMQEndpoint.
+const (
+	EZMQ_TRANSPORT_TCP    = "tcp"
+	EZMQ_TRANSPORT_IPC    = "ipc"
+	EZMQ_TRANSPORT_INPROC = "inproc"
+)
+
+// Separator between transport and address of an endpoint.
+const TRANSPORT_SEPARATOR = "://"
+
+// Structure represents an endpoint to bind/connect to. For example:
+//
+//	tcp://*:5562           [all interfaces]
+//	tcp://eth0:5562        [specific interface]
+//	tcp://[::1]:5562       [IPv6 address]
+//	ipc:///tmp/ezmq.ipc    [same host]
+//	inproc://ezmq          [same EZMQAPI instance]
+type EZMQEndpoint struct {
+	transport string
+	address   string
+	host      string
+	port      int
+}
+
+// Constructs EZMQEndpoint from the given URI.
+func GetEZMQEndpoint(uri string) (*EZMQEndpoint, EZMQErrorCode) {
+	index := strings.Index(uri, TRANSPORT_SEPARATOR)
+	if index <= 0 {
+		return nil, EZMQ_INVALID_ENDPOINT
+	}
+	endpoint := &EZMQEndpoint{}
+	endpoint.transport = uri[:index]
+	endpoint.address = uri[index+len(TRANSPORT_SEPARATOR):]
+	endpoint.port = -1
+	if endpoint.address == "" {
+		return nil, EZMQ_INVALID_ENDPOINT
+	}
+
+	switch endpoint.transport {
+	case EZMQ_TRANSPORT_IPC, EZMQ_TRANSPORT_INPROC:
+		return endpoint, EZMQ_OK
+	case EZMQ_TRANSPORT_TCP:
+		host, port, err := net.SplitHostPort(endpoint.address)
+		if nil != err || host == "" {
+			return nil, EZMQ_INVALID_ENDPOINT
+		}
+		endpoint.port, err = strconv.Atoi(port)
+		if nil != err || endpoint.port < 0 || endpoint.port > 65535 {
+			return nil, EZMQ_INVALID_ENDPOINT
+		}
+		endpoint.host = host
+		return endpoint, EZMQ_OK
+	}
+	return nil, EZMQ_INVALID_ENDPOINT
+}
+
+// Constructs TCP EZMQEndpoint for the given host and port. Host can be an IP
+// address [IPv4/IPv6], an interface name or * for all interfaces.
+func GetEZMQTCPEndpoint(host string, port int) *EZMQEndpoint {
+	endpoint := &EZMQEndpoint{}
+	endpoint.transport = EZMQ_TRANSPORT_TCP
+	endpoint.address = net.JoinHostPort(host, strconv.Itoa(port))
+	endpoint.host = host
+	endpoint.port = port
+	return endpoint
+}
+
+// Get transport of endpoint i.e. tcp, ipc or inproc.
+func (endpoint *EZMQEndpoint) GetTransport() string {
+	return endpoint.transport
+}
+
+// Get address of endpoint i.e. the part after transport.
+func (endpoint *EZMQEndpoint) GetAddress() string {
+	return endpoint.address
+}
+
+// Get host of TCP endpoint. Empty for other transports.
+func (endpoint *EZMQEndpoint) GetHost() string {
+	return endpoint.host
+}
+
+// Get port of TCP endpoint. -1 for other transports.
+func (endpoint *EZMQEndpoint) GetPort() int {
+	return endpoint.port
+}
+
+// Get endpoint URI.
+func (endpoint *EZMQEndpoint) String() string {
+	return endpoint.transport + TRANSPORT_SEPARATOR + endpoint.address
+}
+
+// Reports whether endpoint host is an IPv6 address, in which case IPv6 should
+// be enabled on the socket.
+func (endpoint *EZMQEndpoint) isIPv6() bool {
+	return endpoint.transport == EZMQ_TRANSPORT_TCP && strings.Contains(endpoint.host, ":")
+}
+
+// Enable IPv6 on the socket if required for endpoint.
+func setEndpointOptions(socket *zmq.Socket, endpoint *EZMQEndpoint) error {
+	if endpoint.isIPv6() {
+		err := socket.SetIpv6(true)
+		if nil != err {
+			return newError(EZMQ_SOCKET_ERROR, "enable ipv6", err)
+		}
+	}
+	return nil
+}
+
+// Bind the socket on endpoint if bind is true, otherwise connect it to
+// endpoint.
+func attachSocket(socket *zmq.Socket, endpoint *EZMQEndpoint, bind bool, name string) error {
+	var address string = endpoint.String()
+	if true == bind {
+		err := socket.Bind(address)
+		if nil != err {
+			return newBindError("bind "+name, err)
+		}
+		return nil
+	}
+	err := socket.Connect(address)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "connect "+name, err)
+	}
+	return nil
+}
+
+// Unbind the socket from endpoint if bind is true, otherwise disconnect it
+// from endpoint. Connections made through the endpoint are closed.
+func detachSocket(socket *zmq.Socket, endpoint *EZMQEndpoint, bind bool, name string) error {
+	var err error
+	if true == bind {
+		err = socket.Unbind(endpoint.String())
+	} else {
+		err = socket.Disconnect(endpoint.String())
+	}
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "detach "+name, err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+	"go.uber.org/zap"
+
+	"errors"
+	"sync"
+)
+
+// Structure represents an EZMQ error. It carries the error code returned by
+// the API, the operation which failed and the underlying zmq/proto error [if
+// any].
+//
+// Errors can be checked with errors.Is against the Err* values of this
+// package, which compare error codes, or against the underlying error, for
+// example zmq.EADDRINUSE. Use errors.As to get the EZMQError itself.
+type EZMQError struct {
+	Code EZMQErrorCode
+	Op   string
+	Err  error
+}
+
+// Errors for each EZMQ error code, to be used with errors.Is.
+var (
+	ErrError               = &EZMQError{Code: EZMQ_ERROR}
+	ErrInvalidTopic        = &EZMQError{Code: EZMQ_INVALID_TOPIC}
+	ErrInvalidContentType  = &EZMQError{Code: EZMQ_INVALID_CONTENT_TYPE}
+	ErrInvalidVersion      = &EZMQError{Code: EZMQ_INVALID_VERSION}
+	ErrNotInitialized      = &EZMQError{Code: EZMQ_NOT_INITIALIZED}
+	ErrNotStarted          = &EZMQError{Code: EZMQ_NOT_STARTED}
+	ErrBindInUse           = &EZMQError{Code: EZMQ_BIND_IN_USE}
+	ErrKeyInvalid          = &EZMQError{Code: EZMQ_KEY_INVALID}
+	ErrSerializationFailed = &EZMQError{Code: EZMQ_SERIALIZATION_FAILED}
+	ErrSocket              = &EZMQError{Code: EZMQ_SOCKET_ERROR}
+	ErrCanceled            = &EZMQError{Code: EZMQ_CANCELED}
+	ErrInvalidEndpoint     = &EZMQError{Code: EZMQ_INVALID_ENDPOINT}
+)
+
+func newError(code EZMQErrorCode, op string, err error) *EZMQError {
+	return &EZMQError{Code: code, Op: op, Err: err}
+}
+
+// Error with EZMQ_BIND_IN_USE code if address is already in use, otherwise
+// EZMQ_SOCKET_ERROR.
+func newBindError(op string, err error) *EZMQError {
+	if zmq.EADDRINUSE == zmq.AsErrno(err) {
+		return newError(EZMQ_BIND_IN_USE, op, err)
+	}
+	return newError(EZMQ_SOCKET_ERROR, op, err)
+}
+
+func (ezmqError *EZMQError) Error() string {
+	message := "ezmq: "
+	if ezmqError.Op != "" {
+		message += ezmqError.Op + ": "
+	}
+	message += ezmqError.Code.String()
+	if nil != ezmqError.Err {
+		message += ": " + ezmqError.Err.Error()
+	}
+	return message
+}
+
+// Get the underlying error.
+func (ezmqError *EZMQError) Unwrap() error {
+	return ezmqError.Err
+}
+
+// Reports whether target is an EZMQError with the same code. Op and Err of
+// target are compared only if set.
+func (ezmqError *EZMQError) Is(target error) bool {
+	targetError, ok := target.(*EZMQError)
+	if false == ok {
+		return false
+	}
+	return targetError.Code == ezmqError.Code &&
+		(targetError.Op == "" || targetError.Op == ezmqError.Op) &&
+		(nil == targetError.Err || targetError.Err == ezmqError.Err)
+}
+
+// Get error code of an error returned by this package.
+func GetErrorCode(err error) EZMQErrorCode {
+	if nil == err {
+		return EZMQ_OK
+	}
+	var ezmqError *EZMQError
+	if errors.As(err, &ezmqError) {
+		return ezmqError.Code
+	}
+	return EZMQ_ERROR
+}
+
+// Holds error of the most recent API call, nil if it succeeded.
+type errorHolder struct {
+	mutex sync.Mutex
+	err   error
+}
+
+// Record the result of API call and get its error code. Success clears the
+// error of previous call.
+func (holder *errorHolder) set(err error) EZMQErrorCode {
+	if nil != err && nil != logger {
+		logger.Debug("EZMQ API failed", zap.Error(err))
+	}
+	holder.mutex.Lock()
+	holder.err = err
+	holder.mutex.Unlock()
+	return GetErrorCode(err)
+}
+
+// Record the error [if any] and get it back.
+func (holder *errorHolder) record(err error) error {
+	holder.set(err)
+	return err
+}
+
+func (holder *errorHolder) get() error {
+	holder.mutex.Lock()
+	defer holder.mutex.Unlock()
+	return holder.err
+}
+
+// Get error of the most recent API call on this instance, nil if it succeeded.
+//
+// Note:
+// (1) Error is kept per instance, not per go routine. If APIs are called
+// concurrently, it is the error of the call which completed last. Use the
+// error returned by *Context APIs to get error of a specific call.
+func (ezmqInstance *EZMQAPI) GetLastError() error {
+	return ezmqInstance.lastError.get()
+}
+
+// Get error of the most recent API call on this publisher, nil if it succeeded.
+//
+// Note:
+// (1) Error is kept per publisher, not per go routine. If APIs are called
+// concurrently, it is the error of the call which completed last. Use the
+// error returned by *Context APIs to get error of a specific call.
+func (pubInstance *EZMQPublisher) GetLastError() error {
+	return pubInstance.lastError.get()
+}
+
+// Get error of the most recent API call on this subscriber, nil if it succeeded.
+//
+// Note:
+// (1) Error is kept per subscriber, not per go routine. If APIs are called
+// concurrently, it is the error of the call which completed last. Use the
+// error returned by *Context APIs to get error of a specific call.
+func (subInstance *EZMQSubscriber) GetLastError() error {
+	return subInstance.lastError.get()
+}
@@ -25,4 +25,38 @@ const (
 	EZMQ_ERROR                = 1
 	EZMQ_INVALID_TOPIC        = 2
 	EZMQ_INVALID_CONTENT_TYPE = 3
+	EZMQ_INVALID_VERSION      = 4
+	EZMQ_NOT_INITIALIZED      = 5
+	EZMQ_NOT_STARTED          = 6
+	EZMQ_BIND_IN_USE          = 7
+	EZMQ_KEY_INVALID          = 8
+	EZMQ_SERIALIZATION_FAILED = 9
+	EZMQ_SOCKET_ERROR         = 10
+	EZMQ_CANCELED             = 11
+	EZMQ_INVALID_ENDPOINT     = 12
 )
+
+var errorCodeNames = map[EZMQErrorCode]string{
+	EZMQ_OK:                   "ok",
+	EZMQ_ERROR:                "error",
+	EZMQ_INVALID_TOPIC:        "invalid topic",
+	EZMQ_INVALID_CONTENT_TYPE: "invalid content type",
+	EZMQ_INVALID_VERSION:      "invalid header version",
+	EZMQ_NOT_INITIALIZED:      "not initialized",
+	EZMQ_NOT_STARTED:          "not started",
+	EZMQ_BIND_IN_USE:          "address in use",
+	EZMQ_KEY_INVALID:          "invalid key",
+	EZMQ_SERIALIZATION_FAILED: "serialization failed",
+	EZMQ_SOCKET_ERROR:         "socket error",
+	EZMQ_CANCELED:             "canceled",
+	EZMQ_INVALID_ENDPOINT:     "invalid endpoint",
+}
+
+// Get error code description.
+func (code EZMQErrorCode) String() string {
+	name, exists := errorCodeNames[code]
+	if false == exists {
+		return "unknown error"
+	}
+	return name
+}
@@ -0,0 +1,198 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"time"
+)
+
+// Constants represents EZMQ header versions.
+const (
+	EZMQ_HEADER_VERSION_1 = 1
+	EZMQ_HEADER_VERSION_2 = 2
+
+	// Header version used by publisher by default.
+	EZMQ_HEADER_VERSION = EZMQ_HEADER_VERSION_2
+)
+
+// Length of extended content type which follows the header byte.
+const extendedContentTypeLength = 2
+
+// Length of version 2 fields: publisher ID, sequence number, timestamp and
+// message ID. Each field is of 8 bytes.
+const headerV2FieldsLength = 32
+
+// Callback to get all the subscribed events along with topic and EZMQ header.
+// Topic will be empty for events published without topic.
+type EZMQSubHeaderCB func(topic string, header EZMQHeader, event EZMQMessage)
+
+// Structure represents EZMQ header of a received message.
+//
+// PublisherID, SequenceNumber, Timestamp and MessageID are set only if
+// publisher uses header version 2.
+type EZMQHeader struct {
+	Version        int
+	ContentType    EZMQContentType
+	PublisherID    uint64
+	SequenceNumber uint64
+	Timestamp      time.Time
+	MessageID      uint64
+}
+
+// Header state of a publisher. Sequence numbers are maintained per topic and
+// message ID per publisher.
+type headerState struct {
+	version     int
+	publisherID uint64
+	messageID   uint64
+	sequences   map[string]uint64
+}
+
+func newHeaderState() *headerState {
+	state := &headerState{}
+	state.version = EZMQ_HEADER_VERSION
+	state.reset()
+	return state
+}
+
+// Generate a new publisher ID and restart sequence numbers.
+func (state *headerState) reset() {
+	state.publisherID = rand.Uint64()
+	state.messageID = 0
+	state.sequences = make(map[string]uint64)
+}
+
+// Form the EZMQ header for next message on the topic. Sequence number and
+// message ID are only reserved here; they are consumed by commit once the
+// message is sent, so that a failed send does not leave a gap that
+// subscribers would report as loss.
+func (state *headerState) next(topic string, contentType EZMQContentType) []byte {
+	header := getHeader(state.version, contentType)
+	if EZMQ_HEADER_VERSION_2 != state.version {
+		return header
+	}
+	var fields [headerV2FieldsLength]byte
+	binary.BigEndian.PutUint64(fields[0:], state.publisherID)
+	binary.BigEndian.PutUint64(fields[8:], state.sequences[topic]+1)
+	binary.BigEndian.PutUint64(fields[16:], uint64(time.Now().UnixNano()))
+	binary.BigEndian.PutUint64(fields[24:], state.messageID+1)
+	return append(header, fields[:]...)
+}
+
+// Consume the sequence number and message ID reserved by next for a message
+// sent on the topic.
+func (state *headerState) commit(topic string) {
+	if EZMQ_HEADER_VERSION_2 != state.version {
+		return
+	}
+	state.messageID++
+	state.sequences[topic]++
+}
+
+// Form the EZMQ header.
+//
+// First byte of header: [content type: 3 bits][version: 3 bits][reserved: 2 bits]
+//
+// Application-defined content types do not fit in 3 bits, so content type
+// bits are set to EZMQ_CONTENT_TYPE_EXTENDED and actual content type follows
+// the first byte as 2 bytes in big-endian order.
+//
+// Version 2 header is followed by publisher ID, sequence number, timestamp
+// [nanoseconds since epoch] and message ID, each as 8 bytes in big-endian order.
+func getHeader(headerVersion int, content EZMQContentType) []byte {
+
+	var ezmqHeader byte = 0x00
+	var version byte = (byte)(headerVersion)
+	var contentType byte = (byte)(content)
+	var isExtended bool = content >= EZMQ_CONTENT_TYPE_APPLICATION
+	if isExtended {
+		contentType = EZMQ_CONTENT_TYPE_EXTENDED
+	}
+
+	version = (byte)(version << 2)
+	ezmqHeader = (byte)(ezmqHeader | version)
+	contentType = (byte)(contentType << 5)
+	ezmqHeader = (byte)(ezmqHeader | contentType)
+
+	header := make([]byte, 1, 1+extendedContentTypeLength+headerV2FieldsLength)
+	header[0] = ezmqHeader
+	if isExtended {
+		header = append(header, (byte)(content>>8), (byte)(content))
+	}
+	return header
+}
+
+// Parse the EZMQ header. Headers with unknown version are rejected with
+// EZMQ_INVALID_VERSION.
+func parseHeader(header []byte) (EZMQHeader, EZMQErrorCode) {
+	var ezmqHeader EZMQHeader
+	if len(header) == 0 {
+		logger.Error("Empty header")
+		return ezmqHeader, EZMQ_ERROR
+	}
+	ezmqHeader.Version = (int)((header[0] >> 2) & 0x07)
+	if EZMQ_HEADER_VERSION_1 != ezmqHeader.Version && EZMQ_HEADER_VERSION_2 != ezmqHeader.Version {
+		logger.Error("Unknown header version")
+		return ezmqHeader, EZMQ_INVALID_VERSION
+	}
+	ezmqHeader.ContentType = (EZMQContentType)(header[0] >> 5)
+	header = header[1:]
+	if EZMQ_CONTENT_TYPE_EXTENDED == ezmqHeader.ContentType {
+		if len(header) < extendedContentTypeLength {
+			logger.Error("Invalid extended content type")
+			return ezmqHeader, EZMQ_INVALID_CONTENT_TYPE
+		}
+		ezmqHeader.ContentType = (EZMQContentType)(binary.BigEndian.Uint16(header))
+		header = header[extendedContentTypeLength:]
+	}
+	if EZMQ_HEADER_VERSION_1 == ezmqHeader.Version {
+		return ezmqHeader, EZMQ_OK
+	}
+	if len(header) < headerV2FieldsLength {
+		logger.Error("Invalid version 2 header length")
+		return ezmqHeader, EZMQ_ERROR
+	}
+	ezmqHeader.PublisherID = binary.BigEndian.Uint64(header[0:])
+	ezmqHeader.SequenceNumber = binary.BigEndian.Uint64(header[8:])
+	ezmqHeader.Timestamp = time.Unix(0, int64(binary.BigEndian.Uint64(header[16:])))
+	ezmqHeader.MessageID = binary.BigEndian.Uint64(header[24:])
+	return ezmqHeader, EZMQ_OK
+}
+
+// Set EZMQ header version used by publisher. Version 1 header carries only
+// content type, version 2 header [default] additionally carries publisher ID,
+// sequence number, timestamp and message ID.
+func (pubInstance *EZMQPublisher) SetHeaderVersion(version int) EZMQErrorCode {
+	if EZMQ_HEADER_VERSION_1 != version && EZMQ_HEADER_VERSION_2 != version {
+		return pubInstance.lastError.set(newError(EZMQ_INVALID_VERSION, "set header version", nil))
+	}
+	pubInstance.mutex.Lock()
+	defer pubInstance.mutex.Unlock()
+	pubInstance.header.version = version
+	return pubInstance.lastError.set(nil)
+}
+
+// Set callback to get subscribed events along with EZMQ header. If set, it
+// will be called instead of EZMQSubCB/EZMQSubTopicCB.
+func (subInstance *EZMQSubscriber) SetSubHeaderCallback(subHeaderCallback EZMQSubHeaderCB) {
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	subInstance.subHeaderCallback = subHeaderCallback
+}
@@ -0,0 +1,136 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"github.com/golang/protobuf/jsonpb"
+	proto "github.com/golang/protobuf/proto"
+
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+var errEmptyJSONData = errors.New("JSON data and value are not set")
+
+// Structure represents EZMQJSONData.
+//
+// On publish, JSONData is sent as it is if set, otherwise Value is serialized
+// using encoding/json. Received JSON messages will have JSONData set.
+type EZMQJSONData struct {
+	JSONData []byte
+	Value    interface{}
+}
+
+// Get JSON data
+func (dataInstance *EZMQJSONData) GetJSONData() []byte {
+	return dataInstance.JSONData
+}
+
+// Set JSON data. Data should be a valid JSON document.
+func (dataInstance *EZMQJSONData) SetJSONData(jsonData []byte) EZMQErrorCode {
+	if nil == jsonData || false == json.Valid(jsonData) {
+		return EZMQ_ERROR
+	}
+	dataInstance.JSONData = jsonData
+	return EZMQ_OK
+}
+
+// Set value to be serialized on publish.
+func (dataInstance *EZMQJSONData) SetValue(value interface{}) EZMQErrorCode {
+	if nil == value {
+		return EZMQ_ERROR
+	}
+	dataInstance.JSONData = nil
+	dataInstance.Value = value
+	return EZMQ_OK
+}
+
+// Unmarshal JSON data into the given value.
+func (dataInstance *EZMQJSONData) GetValue(value interface{}) EZMQErrorCode {
+	if nil == dataInstance.JSONData {
+		return EZMQ_ERROR
+	}
+	err := json.Unmarshal(dataInstance.JSONData, value)
+	if nil != err {
+		return EZMQ_ERROR
+	}
+	return EZMQ_OK
+}
+
+// Set event as canonical protobuf JSON.
+func (dataInstance *EZMQJSONData) SetEvent(event Event) EZMQErrorCode {
+	return dataInstance.setProtoMessage(&event)
+}
+
+// Get event from canonical protobuf JSON.
+func (dataInstance *EZMQJSONData) GetEvent() (Event, EZMQErrorCode) {
+	var event Event
+	result := dataInstance.getProtoMessage(&event)
+	return event, result
+}
+
+// Set reading as canonical protobuf JSON.
+func (dataInstance *EZMQJSONData) SetReading(reading Reading) EZMQErrorCode {
+	return dataInstance.setProtoMessage(&reading)
+}
+
+// Get reading from canonical protobuf JSON.
+func (dataInstance *EZMQJSONData) GetReading() (Reading, EZMQErrorCode) {
+	var reading Reading
+	result := dataInstance.getProtoMessage(&reading)
+	return reading, result
+}
+
+// Get Content type
+func (dataInstance EZMQJSONData) GetContentType() EZMQContentType {
+	return EZMQ_CONTENT_TYPE_JSON
+}
+
+func (dataInstance *EZMQJSONData) setProtoMessage(message proto.Message) EZMQErrorCode {
+	var buffer bytes.Buffer
+	marshaler := jsonpb.Marshaler{}
+	err := marshaler.Marshal(&buffer, message)
+	if nil != err {
+		return EZMQ_ERROR
+	}
+	dataInstance.JSONData = buffer.Bytes()
+	dataInstance.Value = nil
+	return EZMQ_OK
+}
+
+func (dataInstance *EZMQJSONData) getProtoMessage(message proto.Message) EZMQErrorCode {
+	if nil == dataInstance.JSONData {
+		return EZMQ_ERROR
+	}
+	err := jsonpb.Unmarshal(bytes.NewReader(dataInstance.JSONData), message)
+	if nil != err {
+		return EZMQ_ERROR
+	}
+	return EZMQ_OK
+}
+
+func (dataInstance EZMQJSONData) marshal() ([]byte, error) {
+	if nil != dataInstance.JSONData {
+		return dataInstance.JSONData, nil
+	}
+	if nil == dataInstance.Value {
+		return nil, errEmptyJSONData
+	}
+	return json.Marshal(dataInstance.Value)
+}
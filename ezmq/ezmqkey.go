@@ -0,0 +1,185 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+
+	"runtime"
+)
+
+// Length of decoded CURVE key.
+const CURVE_KEY_BYTES = 32
+
+// Characters of Z85 encoding, in the order of their values.
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+// Check that key is a Z85 encoded CURVE key. Key is validated without
+// converting it to string, so that secret keys are not copied.
+func isValidKey(key []byte) bool {
+	if len(key) != PUB_KEY_LENGTH {
+		return false
+	}
+	for n := 0; n < len(key); n += 5 {
+		var value uint64 = 0
+		for _, char := range key[n : n+5] {
+			index := indexZ85(char)
+			if index < 0 {
+				return false
+			}
+			value = value*85 + uint64(index)
+		}
+		if value > 0xFFFFFFFF {
+			return false
+		}
+	}
+	return true
+}
+
+func indexZ85(char byte) int {
+	for index := 0; index < len(z85Alphabet); index++ {
+		if z85Alphabet[index] == char {
+			return index
+		}
+	}
+	return -1
+}
+
+// Secured mode needs ZeroMQ library with CURVE support [libsodium]. Unsecured
+// publishers and subscribers do not need it.
+func checkCurve(op string) error {
+	if false == zmq.HasCurve() {
+		logger.Error("CURVE is not supported by ZeroMQ library")
+		return newError(EZMQ_ERROR, op, nil)
+	}
+	return nil
+}
+
+// Copy key into a buffer owned by ezmq, so that it can be wiped without
+// changing the buffer of application.
+func copyKey(key []byte) []byte {
+	return append([]byte(nil), key...)
+}
+
+// Overwrite key material with zeros.
+func wipe(buffer []byte) {
+	for index := range buffer {
+		buffer[index] = 0
+	}
+	runtime.KeepAlive(buffer)
+}
+
+func (pubInstance *EZMQPublisher) wipeKeys() {
+	wipe(pubInstance.serverSecretKey)
+	pubInstance.serverSecretKey = nil
+}
+
+// Stops publisher if it is started and wipes its secret key.
+//
+// Note:
+// (1) Publisher should not be used after this API, as secured publisher can
+// not be started without setting key again.
+func (pubInstance *EZMQPublisher) Close() EZMQErrorCode {
+	var result EZMQErrorCode = EZMQ_OK
+	pubInstance.mutex.Lock()
+	var started bool = nil != pubInstance.publisher
+	pubInstance.mutex.Unlock()
+	if true == started {
+		result = pubInstance.Stop()
+	}
+	pubInstance.mutex.Lock()
+	defer pubInstance.mutex.Unlock()
+	pubInstance.wipeKeys()
+	return result
+}
+
+// Apply CURVE keys on subscriber socket to connect to the server with given
+// public key. Once client keys are applied, only server public key is changed
+// as client keys are kept by socket, and private key is wiped.
+func (subInstance *EZMQSubscriber) applyClientKeys(serverPublicKey []byte) error {
+	mechanism, err := subInstance.subscriber.GetMechanism()
+	if nil == err && zmq.CURVE == mechanism {
+		err = subInstance.subscriber.SetCurveServerkey(string(serverPublicKey))
+	} else if len(subInstance.clientSecretKey) != SUB_KEY_LENGTH {
+		// key is wiped after previous start
+		return newError(EZMQ_KEY_INVALID, "set client keys", nil)
+	} else {
+		err = subInstance.subscriber.ClientAuthCurve(string(serverPublicKey), string(subInstance.clientPublicKey),
+			string(subInstance.clientSecretKey))
+		if nil == err {
+			subInstance.wipeKeys()
+		}
+	}
+	if nil != err {
+		return newError(EZMQ_KEY_INVALID, "set client keys", err)
+	}
+	return nil
+}
+
+func (subInstance *EZMQSubscriber) wipeKeys() {
+	wipe(subInstance.clientSecretKey)
+	subInstance.clientSecretKey = nil
+}
+
+// Stops subscriber if it is started and wipes its private key and password.
+//
+// Note:
+// (1) Subscriber should not be used after this API, as secured subscriber
+// can not be started without setting keys again.
+func (subInstance *EZMQSubscriber) Close() EZMQErrorCode {
+	var result EZMQErrorCode = EZMQ_OK
+	subInstance.mutex.Lock()
+	// sockets are left open if start failed
+	var started bool = nil != subInstance.subscriber || nil != subInstance.shutdownServer
+	subInstance.mutex.Unlock()
+	if true == started {
+		result = subInstance.Stop()
+	}
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	subInstance.wipeKeys()
+	wipe(subInstance.password)
+	subInstance.password = nil
+	return result
+}
+
+func (brokerInstance *EZMQBroker) wipeKeys() {
+	wipe(brokerInstance.frontendClientSecretKey)
+	brokerInstance.frontendClientSecretKey = nil
+	wipe(brokerInstance.backendSecretKey)
+	brokerInstance.backendSecretKey = nil
+}
+
+// Stops broker if it is started and wipes its secret keys.
+//
+// Note:
+// (1) Broker should not be used after this API, as secured broker can not be
+// started without setting keys again.
+func (brokerInstance *EZMQBroker) Close() EZMQErrorCode {
+	var result EZMQErrorCode = EZMQ_OK
+	brokerInstance.mutex.Lock()
+	var started bool = nil != brokerInstance.frontend
+	brokerInstance.mutex.Unlock()
+	if true == started {
+		result = brokerInstance.Stop()
+	}
+	brokerInstance.mutex.Lock()
+	defer brokerInstance.mutex.Unlock()
+	brokerInstance.wipeKeys()
+	return result
+}
@@ -0,0 +1,167 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"strings"
+	"time"
+)
+
+// Streams without messages for this duration are forgotten. Publisher gets a
+// new ID on every start, so streams of stopped publishers are not kept
+// forever.
+const lossStreamExpiry = time.Hour
+
+// Interval at which expired streams are removed.
+const lossSweepInterval = time.Minute
+
+// Callback to get message loss detected by subscriber.
+type EZMQLossCB func(report EZMQLossReport)
+
+// Structure represents a message loss or duplicate detected by subscriber on
+// a stream, which is identified by publisher ID and topic.
+//
+// Lost is number of messages missing between ExpectedSequence and
+// ReceivedSequence, it will be 0 if IsDuplicate is true. Total counts are
+// maintained per stream from the first message received on it.
+type EZMQLossReport struct {
+	PublisherID      uint64
+	Topic            string
+	ExpectedSequence uint64
+	ReceivedSequence uint64
+	Lost             uint64
+	IsDuplicate      bool
+	TotalReceived    uint64
+	TotalLost        uint64
+	TotalDuplicates  uint64
+}
+
+type lossStreamKey struct {
+	publisherID uint64
+	topic       string
+}
+
+type lossStream struct {
+	lastSequence uint64
+	received     uint64
+	lost         uint64
+	duplicates   uint64
+	lastSeen     time.Time
+}
+
+// Tracks sequence numbers of version 2 headers per publisher and topic.
+type lossDetector struct {
+	streams   map[lossStreamKey]*lossStream
+	lastSweep time.Time
+}
+
+func newLossDetector() *lossDetector {
+	detector := &lossDetector{}
+	detector.reset()
+	return detector
+}
+
+func (detector *lossDetector) reset() {
+	detector.streams = make(map[lossStreamKey]*lossStream)
+	detector.lastSweep = time.Now()
+}
+
+// Remove streams which have no message since lossStreamExpiry.
+func (detector *lossDetector) sweep(now time.Time) {
+	if now.Sub(detector.lastSweep) < lossSweepInterval {
+		return
+	}
+	detector.lastSweep = now
+	for key, stream := range detector.streams {
+		if now.Sub(stream.lastSeen) > lossStreamExpiry {
+			delete(detector.streams, key)
+		}
+	}
+}
+
+// Forget streams of the given topic and its sub-topics, or of the topics
+// matching the given wildcard topic. Used on un-subscribe, so that
+// re-subscribing later will not be reported as loss.
+func (detector *lossDetector) forget(topic string) {
+	wildcard := isWildcardTopic(topic)
+	for key := range detector.streams {
+		if wildcard && matchTopicPattern(topic, key.topic) {
+			delete(detector.streams, key)
+		} else if false == wildcard && strings.HasPrefix(key.topic+"/", topic) {
+			delete(detector.streams, key)
+		}
+	}
+}
+
+// Check sequence number of received message. Returns report and true if a
+// gap or duplicate is detected.
+func (detector *lossDetector) check(topic string, header EZMQHeader) (EZMQLossReport, bool) {
+	var report EZMQLossReport
+	if EZMQ_HEADER_VERSION_2 != header.Version {
+		return report, false
+	}
+	now := time.Now()
+	detector.sweep(now)
+	key := lossStreamKey{header.PublisherID, topic}
+	stream, exists := detector.streams[key]
+	if false == exists {
+		detector.streams[key] = &lossStream{lastSequence: header.SequenceNumber, received: 1, lastSeen: now}
+		return report, false
+	}
+	stream.lastSeen = now
+	stream.received++
+	expected := stream.lastSequence + 1
+	if header.SequenceNumber == expected {
+		stream.lastSequence = header.SequenceNumber
+		return report, false
+	}
+	if header.SequenceNumber > expected {
+		report.Lost = header.SequenceNumber - expected
+		stream.lost += report.Lost
+		stream.lastSequence = header.SequenceNumber
+	} else {
+		report.IsDuplicate = true
+		stream.duplicates++
+	}
+	report.PublisherID = header.PublisherID
+	report.Topic = topic
+	report.ExpectedSequence = expected
+	report.ReceivedSequence = header.SequenceNumber
+	report.TotalReceived = stream.received
+	report.TotalLost = stream.lost
+	report.TotalDuplicates = stream.duplicates
+	return report, true
+}
+
+// Set callback to get message loss and duplicates detected using sequence
+// numbers of version 2 headers.
+//
+// Note:
+// (1) Callback is called before the message is given to subscriber callbacks. Duplicate messages are still given to them.
+//
+// (2) Loss is not detected for publishers using version 1 header.
+//
+// (3) Tracking is restarted on Stop(), and for a topic on its un-subscribe.
+//
+// (4) Streams without messages for an hour are forgotten, as publisher gets a
+// new ID on restart. Tracking of such a stream restarts with its next message.
+func (subInstance *EZMQSubscriber) SetLossCallback(lossCallback EZMQLossCB) {
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	subInstance.lossCallback = lossCallback
+}
@@ -0,0 +1,342 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+	"go.uber.org/zap"
+
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type EZMQEventType int
+
+// Constants represents types of socket events.
+const (
+	EZMQ_EVENT_CONNECTED        = 0
+	EZMQ_EVENT_DISCONNECTED     = 1
+	EZMQ_EVENT_CONNECT_RETRIED  = 2
+	EZMQ_EVENT_ACCEPTED         = 3
+	EZMQ_EVENT_HANDSHAKE_FAILED = 4
+)
+
+var eventTypeNames = map[EZMQEventType]string{
+	EZMQ_EVENT_CONNECTED:        "connected",
+	EZMQ_EVENT_DISCONNECTED:     "disconnected",
+	EZMQ_EVENT_CONNECT_RETRIED:  "connect retried",
+	EZMQ_EVENT_ACCEPTED:         "accepted",
+	EZMQ_EVENT_HANDSHAKE_FAILED: "handshake failed",
+}
+
+// Get name of the event type.
+func (eventType EZMQEventType) String() string {
+	name, exists := eventTypeNames[eventType]
+	if false == exists {
+		return "unknown event"
+	}
+	return name
+}
+
+// Structure represents a socket event of publisher/subscriber.
+//
+// Endpoint is the address reported by ZeroMQ, which is the peer endpoint for
+// connecting sockets and the bound endpoint for accepted connections. Peer is
+// the endpoint of connected peer [for example tcp://192.168.1.5:41234], it is
+// empty if peer address is not known. Peer of accepted connections is
+// resolved from the socket descriptor when event is processed, so it is best
+// effort and is always empty on Windows. Value is specific to event: reconnect
+// interval in milliseconds for retried event and error code for handshake
+// failure.
+type EZMQEvent struct {
+	Type     EZMQEventType
+	Endpoint string
+	Peer     string
+	Value    int
+}
+
+// Callback to get socket events of publisher/subscriber.
+type EZMQEventCB func(event EZMQEvent)
+
+// Monitors events of a socket on a go routine. Only one monitor can be set on
+// a ZeroMQ socket, so all the users of socket events are served from here.
+type socketMonitor struct {
+	bound        bool
+	errorHandler EZMQErrorCB
+	eventHandler EZMQEventCB
+	closing      int32
+	unbinding    int32
+	connected    chan struct{}
+	done         chan struct{}
+
+	// used only on monitor go routine
+	peers         map[int]string
+	pendingFailed *EZMQEvent
+}
+
+// Start monitoring all the events of socket. Asynchronous socket errors are
+// reported to errorHandler and connection events to eventHandler [if any].
+// Returns nil if socket can not be monitored.
+//
+// Note:
+// (1) Monitor is stopped when socket is closed.
+//
+// (2) Socket is not monitored and nil is returned if there is no handler and
+// connection is not watched.
+func startMonitor(socket *zmq.Socket, bound bool, watchConnect bool, errorHandler EZMQErrorCB,
+	eventHandler EZMQEventCB) *socketMonitor {
+	if nil == errorHandler && nil == eventHandler && false == watchConnect {
+		return nil
+	}
+	var address string = getInProcUniqueAddress()
+	err := socket.Monitor(address, zmq.EVENT_ALL)
+	if nil != err {
+		logger.Info("Error in monitor")
+		return nil
+	}
+	zmqContext, err := socket.Context()
+	if nil != err {
+		socket.Monitor("", 0)
+		return nil
+	}
+	watcher, err := zmqContext.NewSocket(zmq.PAIR)
+	if nil != err {
+		logger.Info("Pair socket creation failed")
+		socket.Monitor("", 0)
+		return nil
+	}
+	err = watcher.Connect(address)
+	if nil != err {
+		logger.Info("Pair socket connection failed")
+		socket.Monitor("", 0)
+		watcher.Close()
+		return nil
+	}
+	monitor := &socketMonitor{bound: bound, errorHandler: errorHandler, eventHandler: eventHandler}
+	monitor.connected = make(chan struct{})
+	monitor.done = make(chan struct{})
+	monitor.peers = make(map[int]string)
+	go monitor.run(watcher)
+	return monitor
+}
+
+func (monitor *socketMonitor) run(watcher *zmq.Socket) {
+	var connected bool = false
+	defer close(monitor.done)
+	defer watcher.Close()
+	for {
+		event, address, value, err := watcher.RecvEvent(0)
+		if nil != err {
+			if zmq.EINTR == zmq.AsErrno(err) {
+				continue
+			}
+			logger.Debug("Monitor stopped", zap.Error(err))
+			return
+		}
+		logger.Debug("Event received", zap.Int("eventType", int(event)), zap.String("address", address),
+			zap.Int("Value", value))
+		if event == zmq.EVENT_CONNECTED && false == connected {
+			connected = true
+			close(monitor.connected)
+		}
+		monitor.reportError(event)
+		monitor.reportEvent(event, address, value)
+		if event == zmq.EVENT_MONITOR_STOPPED {
+			monitor.flushHandshakeFailed()
+			return
+		}
+	}
+}
+
+// Report socket errors to error handler. Bind failures are not reported as
+// bind is synchronous, and closed event is reported as loss of bind only for
+// bound sockets as connecting sockets are closed on every retry.
+func (monitor *socketMonitor) reportError(event zmq.Event) {
+	var code EZMQErrorCode
+	switch event {
+	case zmq.EVENT_ACCEPT_FAILED, zmq.EVENT_CLOSE_FAILED:
+		code = EZMQ_SOCKET_ERROR
+	case zmq.EVENT_CLOSED:
+		if false == monitor.bound || true == monitor.consumeUnbind() {
+			return
+		}
+		code = EZMQ_SOCKET_ERROR
+	case zmq.EVENT_HANDSHAKE_FAILED_NO_DETAIL, zmq.EVENT_HANDSHAKE_FAILED_PROTOCOL:
+		code = EZMQ_SOCKET_ERROR
+	case zmq.EVENT_HANDSHAKE_FAILED_AUTH:
+		code = EZMQ_KEY_INVALID
+	default:
+		return
+	}
+	if nil != monitor.errorHandler && 0 == atomic.LoadInt32(&monitor.closing) {
+		monitor.errorHandler(code)
+	}
+}
+
+// Report connection events to event handler.
+//
+// Note:
+// (1) For bound sockets, peer address is resolved from the socket descriptor
+// given with accepted event and kept till disconnected event of descriptor.
+//
+// (2) Handshake failure has no descriptor. ZeroMQ disconnects the peer right
+// after handshake failure, so failure is held till the disconnected event to
+// report it with the peer address.
+func (monitor *socketMonitor) reportEvent(event zmq.Event, address string, value int) {
+	var eventType EZMQEventType
+	var peer string = address
+	if true == monitor.bound {
+		peer = ""
+	}
+	switch event {
+	case zmq.EVENT_CONNECTED:
+		eventType = EZMQ_EVENT_CONNECTED
+	case zmq.EVENT_DISCONNECTED:
+		eventType = EZMQ_EVENT_DISCONNECTED
+		if true == monitor.bound {
+			peer = monitor.peers[value]
+			delete(monitor.peers, value)
+		}
+	case zmq.EVENT_CONNECT_RETRIED:
+		eventType = EZMQ_EVENT_CONNECT_RETRIED
+	case zmq.EVENT_ACCEPTED:
+		eventType = EZMQ_EVENT_ACCEPTED
+		peer = getPeerAddress(value)
+		monitor.peers[value] = peer
+	case zmq.EVENT_HANDSHAKE_FAILED_NO_DETAIL, zmq.EVENT_HANDSHAKE_FAILED_PROTOCOL, zmq.EVENT_HANDSHAKE_FAILED_AUTH:
+		eventType = EZMQ_EVENT_HANDSHAKE_FAILED
+	default:
+		return
+	}
+	if EZMQ_EVENT_HANDSHAKE_FAILED == eventType && true == monitor.bound {
+		monitor.flushHandshakeFailed()
+		monitor.pendingFailed = &EZMQEvent{Type: eventType, Endpoint: address, Value: value}
+		return
+	}
+	if nil != monitor.pendingFailed {
+		if EZMQ_EVENT_DISCONNECTED == eventType {
+			monitor.pendingFailed.Peer = peer
+		}
+		monitor.flushHandshakeFailed()
+	}
+	monitor.notifyEvent(EZMQEvent{Type: eventType, Endpoint: address, Peer: peer, Value: value})
+}
+
+// Report handshake failure held for peer address, if any.
+func (monitor *socketMonitor) flushHandshakeFailed() {
+	if nil != monitor.pendingFailed {
+		monitor.notifyEvent(*monitor.pendingFailed)
+		monitor.pendingFailed = nil
+	}
+}
+
+func (monitor *socketMonitor) notifyEvent(event EZMQEvent) {
+	if nil != monitor.eventHandler && 0 == atomic.LoadInt32(&monitor.closing) {
+		monitor.eventHandler(event)
+	}
+}
+
+// Mark that an endpoint of socket is being unbound, so that its closed event
+// is not reported as loss of bind.
+func (monitor *socketMonitor) expectUnbind() {
+	if nil == monitor {
+		return
+	}
+	atomic.AddInt32(&monitor.unbinding, 1)
+}
+
+func (monitor *socketMonitor) consumeUnbind() bool {
+	for {
+		count := atomic.LoadInt32(&monitor.unbinding)
+		if count <= 0 {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&monitor.unbinding, count, count-1) {
+			return true
+		}
+	}
+}
+
+// Mark that socket is being closed, so that events of close are not
+// reported.
+func (monitor *socketMonitor) close() {
+	if nil == monitor {
+		return
+	}
+	atomic.StoreInt32(&monitor.closing, 1)
+}
+
+// Wait till socket is connected to a peer.
+func (monitor *socketMonitor) waitConnected(ctx context.Context) error {
+	select {
+	case <-monitor.connected:
+		logger.Debug("Socket connected")
+		return nil
+	case <-monitor.done:
+		return newError(EZMQ_SOCKET_ERROR, "wait for connection", nil)
+	case <-ctx.Done():
+		return newContextError("wait for connection", ctx.Err())
+	}
+}
+
+// Wait till monitor is stopped after socket is closed, for deadline of ctx or
+// given timeout if ctx has no deadline.
+func (monitor *socketMonitor) waitStopped(ctx context.Context, timeout time.Duration) {
+	if _, exists := ctx.Deadline(); false == exists {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	select {
+	case <-monitor.done:
+		logger.Debug("Socket closed")
+	case <-ctx.Done():
+		logger.Debug("Timeout occured for socket close")
+	}
+}
+
+// Set callback to get connection and handshake events of publisher.
+//
+// Note:
+// (1) This API should be called before Start() API.
+//
+// (2) Callback is called on monitor go routine. Events are not reported once
+// publisher is being stopped.
+//
+// (3) Socket is monitored only if event or error callback is set.
+func (pubInstance *EZMQPublisher) SetEventCallback(eventCallback EZMQEventCB) {
+	pubInstance.mutex.Lock()
+	defer pubInstance.mutex.Unlock()
+	pubInstance.eventCallback = eventCallback
+}
+
+// Set callback to get connection and handshake events of subscriber.
+//
+// Note:
+// (1) This API should be called before Start() API.
+//
+// (2) Callback is called on monitor go routine. Events are not reported once
+// subscriber is being stopped.
+//
+// (3) Socket is monitored only if event or error callback is set.
+func (subInstance *EZMQSubscriber) SetEventCallback(eventCallback EZMQEventCB) {
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	subInstance.eventCallback = eventCallback
+}
@@ -0,0 +1,160 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+
+	"time"
+)
+
+// Option to construct EZMQPublisher/EZMQSubscriber.
+type EZMQOption func(options *ezmqOptions)
+
+type ezmqOptions struct {
+	api              *EZMQAPI
+	socketOptions    []socketOption
+	reverse          bool
+	skipUnsubscribed bool
+}
+
+// Socket option applied on publisher/subscriber socket before bind/connect.
+type socketOption func(socket *zmq.Socket) error
+
+// Create publisher/subscriber on the given EZMQAPI instance. By default, the
+// instance returned by GetInstance() is used.
+func WithAPI(api *EZMQAPI) EZMQOption {
+	return func(options *ezmqOptions) {
+		options.api = api
+	}
+}
+
+func getOptions(options []EZMQOption) *ezmqOptions {
+	result := &ezmqOptions{}
+	for _, option := range options {
+		if nil != option {
+			option(result)
+		}
+	}
+	if nil == result.api {
+		result.api = GetInstance()
+	}
+	return result
+}
+
+// Reverse the topology i.e. publisher connects to the endpoint and subscriber
+// binds on it. This allows publishers behind NAT to reach a central
+// subscriber.
+//
+// Note:
+// (1) Topics, header and CURVE security work the same as in normal topology.
+// Publisher stays the CURVE server and subscriber the CURVE client.
+//
+// (2) Publisher and subscriber should both use this option.
+func WithReverseTopology() EZMQOption {
+	return func(options *ezmqOptions) {
+		options.reverse = true
+	}
+}
+
+// Skip marshaling and sending of events on topics which have no subscriber.
+// Publish APIs return EZMQ_OK for skipped events.
+//
+// Note:
+// (1) This option is applicable only for publisher.
+//
+// (2) Subscriptions reach publisher asynchronously, events published just
+// after a subscriber connects can be skipped. Without this option such events
+// are dropped by ZeroMQ as well.
+func WithSkipUnsubscribedTopics() EZMQOption {
+	return func(options *ezmqOptions) {
+		options.skipUnsubscribed = true
+	}
+}
+
+// Set send high water mark of the socket i.e. maximum number of outstanding
+// messages queued per peer. Zero means no limit.
+func WithSendHWM(hwm int) EZMQOption {
+	return withSocketOption(func(socket *zmq.Socket) error {
+		return socket.SetSndhwm(hwm)
+	})
+}
+
+// Set receive high water mark of the socket. Zero means no limit.
+func WithReceiveHWM(hwm int) EZMQOption {
+	return withSocketOption(func(socket *zmq.Socket) error {
+		return socket.SetRcvhwm(hwm)
+	})
+}
+
+// Set how long pending messages are kept after socket is closed. Negative
+// value means infinite.
+func WithLinger(linger time.Duration) EZMQOption {
+	return withSocketOption(func(socket *zmq.Socket) error {
+		return socket.SetLinger(linger)
+	})
+}
+
+// Enable TCP keepalive on the socket.
+//
+// Note:
+// (1) idle and interval are rounded to seconds. Zero value of any parameter
+// means OS default is used.
+func WithTCPKeepAlive(idle time.Duration, interval time.Duration, count int) EZMQOption {
+	return withSocketOption(func(socket *zmq.Socket) error {
+		err := socket.SetTcpKeepalive(1)
+		if nil == err && idle > 0 {
+			err = socket.SetTcpKeepaliveIdle(int(idle / time.Second))
+		}
+		if nil == err && interval > 0 {
+			err = socket.SetTcpKeepaliveIntvl(int(interval / time.Second))
+		}
+		if nil == err && count > 0 {
+			err = socket.SetTcpKeepaliveCnt(count)
+		}
+		return err
+	})
+}
+
+// Set reconnect interval of the socket. If maximum is greater than interval,
+// interval is doubled on each retry till maximum.
+func WithReconnectInterval(interval time.Duration, maximum time.Duration) EZMQOption {
+	return withSocketOption(func(socket *zmq.Socket) error {
+		err := socket.SetReconnectIvl(interval)
+		if nil == err {
+			err = socket.SetReconnectIvlMax(maximum)
+		}
+		return err
+	})
+}
+
+func withSocketOption(option socketOption) EZMQOption {
+	return func(options *ezmqOptions) {
+		options.socketOptions = append(options.socketOptions, option)
+	}
+}
+
+func applySocketOptions(socket *zmq.Socket, socketOptions []socketOption) error {
+	for _, option := range socketOptions {
+		err := option(socket)
+		if nil != err {
+			return newError(EZMQ_SOCKET_ERROR, "set socket option", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+//go:build !windows
+// +build !windows
+
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"go.uber.org/zap"
+
+	"net"
+	"strconv"
+	"syscall"
+)
+
+// Get endpoint of peer connected on given socket descriptor. Empty string is
+// returned if address can not be resolved.
+func getPeerAddress(fd int) string {
+	address, err := syscall.Getpeername(fd)
+	if nil != err {
+		logger.Debug("Error in getting peer address", zap.Error(err))
+		return ""
+	}
+	var host string
+	switch peer := address.(type) {
+	case *syscall.SockaddrInet4:
+		host = net.JoinHostPort(net.IP(peer.Addr[:]).String(), strconv.Itoa(peer.Port))
+	case *syscall.SockaddrInet6:
+		host = net.JoinHostPort(net.IP(peer.Addr[:]).String(), strconv.Itoa(peer.Port))
+	case *syscall.SockaddrUnix:
+		if "" != peer.Name {
+			return EZMQ_TRANSPORT_IPC + TRANSPORT_SEPARATOR + peer.Name
+		}
+		return ""
+	default:
+		return ""
+	}
+	return EZMQ_TRANSPORT_TCP + TRANSPORT_SEPARATOR + host
+}
@@ -0,0 +1,148 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+
+	"crypto/subtle"
+	"sync"
+)
+
+// Store of user credentials used by publisher for PLAIN authentication.
+//
+// Note:
+// (1) Authenticate is called on authentication go routine of ezmq, it should
+// not block for long as other connections wait for it.
+type EZMQCredentialStore interface {
+	// Returns true if the password of user is valid.
+	Authenticate(username string, password []byte) bool
+}
+
+// Credential store which keeps passwords of users in memory.
+type EZMQPasswordStore struct {
+	mutex     sync.Mutex
+	passwords map[string][]byte
+}
+
+// Constructs empty EZMQPasswordStore.
+func GetEZMQPasswordStore() *EZMQPasswordStore {
+	store := &EZMQPasswordStore{}
+	store.passwords = make(map[string][]byte)
+	return store
+}
+
+// Add user with the given password, password of existing user is replaced.
+func (store *EZMQPasswordStore) AddUser(username string, password []byte) EZMQErrorCode {
+	if username == "" {
+		return EZMQ_ERROR
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.passwords[username] = append([]byte(nil), password...)
+	return EZMQ_OK
+}
+
+// Remove user from store.
+func (store *EZMQPasswordStore) RemoveUser(username string) EZMQErrorCode {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, exists := store.passwords[username]; false == exists {
+		return EZMQ_ERROR
+	}
+	delete(store.passwords, username)
+	return EZMQ_OK
+}
+
+// Returns true if the password of user is valid.
+func (store *EZMQPasswordStore) Authenticate(username string, password []byte) bool {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	expected, exists := store.passwords[username]
+	return exists && 1 == subtle.ConstantTimeCompare(expected, password)
+}
+
+// Set credential store to authenticate subscribers with PLAIN mechanism.
+// Subscribers should set their credentials using SetCredentials API.
+//
+// Note:
+// (1) This API should be called before Start() API.
+//
+// (2) PLAIN sends passwords in clear text, it should be used only on links
+// which are already secured, e.g. tunneled. It can not be used along with
+// server private key of secured mode.
+//
+// (3) Credentials are checked by authentication of publisher. Notes of
+// AddClientKey API on other ZAP handlers apply to this API as well.
+func (pubInstance *EZMQPublisher) SetCredentialStore(store EZMQCredentialStore) {
+	pubInstance.authPolicy.mutex.Lock()
+	defer pubInstance.authPolicy.mutex.Unlock()
+	pubInstance.authPolicy.credentials = store
+}
+
+// Enable PLAIN mechanism on publisher socket if credential store is set.
+func (pubInstance *EZMQPublisher) setPlainServer() error {
+	pubInstance.authPolicy.mutex.Lock()
+	var store EZMQCredentialStore = pubInstance.authPolicy.credentials
+	pubInstance.authPolicy.mutex.Unlock()
+	if nil == store {
+		return nil
+	}
+	mechanism, err := pubInstance.publisher.GetMechanism()
+	if nil == err && zmq.CURVE == mechanism {
+		return newError(EZMQ_ERROR, "set credential store", nil)
+	}
+	err = pubInstance.publisher.SetPlainServer(1)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "set plain server", err)
+	}
+	return nil
+}
+
+// Set credentials of subscriber for PLAIN authentication by publisher.
+//
+// Note:
+// (1) This API should be called before Start() API.
+//
+// (2) It can not be used along with client keys of secured mode.
+func (subInstance *EZMQSubscriber) SetCredentials(username string, password []byte) EZMQErrorCode {
+	if username == "" {
+		return subInstance.lastError.set(newError(EZMQ_ERROR, "set credentials", nil))
+	}
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	subInstance.username = username
+	subInstance.password = append([]byte(nil), password...)
+	return subInstance.lastError.set(nil)
+}
+
+// Enable PLAIN mechanism on subscriber socket if credentials are set.
+func (subInstance *EZMQSubscriber) setPlainClient() error {
+	if subInstance.username == "" {
+		return nil
+	}
+	mechanism, err := subInstance.subscriber.GetMechanism()
+	if nil == err && zmq.CURVE == mechanism {
+		return newError(EZMQ_ERROR, "set credentials", nil)
+	}
+	err = subInstance.subscriber.ClientAuthPlain(subInstance.username, string(subInstance.password))
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "set credentials", err)
+	}
+	return nil
+}
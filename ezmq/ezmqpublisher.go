@@ -1,16 +1,12 @@
-// +build unsecure
-
 package ezmq
 
 import (
-	proto "github.com/golang/protobuf/proto"
 	zmq "github.com/pebbe/zmq4"
 	"go.uber.org/zap"
 
 	List "container/list"
-	"math/rand"
+	"context"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -22,39 +18,79 @@ const PUB_TCP_PREFIX = "tcp://*:"
 // Regex Pattern for Topic validation.
 const TOPIC_PATTERN = "^[a-zA-Z0-9-_./]+$"
 
-// Callback to get error code for start of EZMQ publisher.
-// [As of now, Not being used]
+// Publisher key length
+const PUB_KEY_LENGTH = 40
+
+// Callback to get error code for start of EZMQ publisher/subscriber.
 type EZMQStartCB func(code EZMQErrorCode)
 
-// Callback to get error code for stop of EZMQ publisher.
-// [As of now, Not being used]
+// Callback to get error code for stop of EZMQ publisher/subscriber.
 type EZMQStopCB func(code EZMQErrorCode)
 
-// Error Callback for start/stop of EZMQ publisher.
-// [As of now, Not being used]
+// Callback to get asynchronous errors of EZMQ publisher/subscriber after
+// start, such as send errors, loss of bind and handshake failures.
 type EZMQErrorCB func(code EZMQErrorCode)
 
 //Structure represents EZMQPublisher.
 type EZMQPublisher struct {
-	port          int
-	startCallback EZMQStartCB
-	stopCallback  EZMQStopCB
-	errorCallback EZMQErrorCB
+	port            int
+	endpoint        *EZMQEndpoint
+	startCallback   EZMQStartCB
+	stopCallback    EZMQStopCB
+	errorCallback   EZMQErrorCB
+	serverSecretKey []byte
 
 	publisher *zmq.Socket
 	context   *zmq.Context
 	mutex     *sync.Mutex
+	header    *headerState
+	options   []socketOption
+	reverse   bool
+	lastError errorHolder
+
+	subscriptionCallback EZMQSubscriptionCB
+	eventCallback        EZMQEventCB
+	subscriptions        map[string]bool
+	skipUnsubscribed     bool
+	trackerStop          chan struct{}
+	monitor              *socketMonitor
+	zapDomain            string
+	authPolicy           *zapPolicy
+	zapHandler           *zapHandler
+	rotationEndpoint     *EZMQEndpoint
+	secured              bool
+}
+
+// Constructs EZMQPublisher which binds on given port of all interfaces.
+//
+// Note:
+// (1) Publisher is created on the EZMQAPI instance given by WithAPI option,
+// otherwise on the instance returned by GetInstance().
+func GetEZMQPublisher(port int, startCallback EZMQStartCB, stopCallback EZMQStopCB, errorCallback EZMQErrorCB,
+	options ...EZMQOption) *EZMQPublisher {
+	return GetEZMQPublisherWithEndpoint(GetEZMQTCPEndpoint("*", port), startCallback, stopCallback, errorCallback,
+		options...)
 }
 
-// Constructs EZMQPublisher.
-func GetEZMQPublisher(port int, startCallback EZMQStartCB, stopCallback EZMQStopCB, errorCallback EZMQErrorCB) *EZMQPublisher {
+// Constructs EZMQPublisher which binds on given endpoint.
+//
+// Note:
+// (1) Publisher is created on the EZMQAPI instance given by WithAPI option,
+// otherwise on the instance returned by GetInstance().
+func GetEZMQPublisherWithEndpoint(endpoint *EZMQEndpoint, startCallback EZMQStartCB, stopCallback EZMQStopCB,
+	errorCallback EZMQErrorCB, options ...EZMQOption) *EZMQPublisher {
+	if nil == endpoint {
+		return nil
+	}
 	var instance *EZMQPublisher
+	var config *ezmqOptions = getOptions(options)
 	instance = &EZMQPublisher{}
-	instance.port = port
+	instance.endpoint = endpoint
+	instance.port = endpoint.GetPort()
 	instance.startCallback = startCallback
 	instance.stopCallback = stopCallback
 	instance.errorCallback = errorCallback
-	instance.context = GetInstance().GetContext()
+	instance.context = config.api.GetContext()
 
 	if nil == instance.context {
 		logger.Error("Context is null")
@@ -62,122 +98,210 @@ func GetEZMQPublisher(port int, startCallback EZMQStartCB, stopCallback EZMQStop
 	}
 	instance.publisher = nil
 	instance.mutex = &sync.Mutex{}
+	instance.header = newHeaderState()
+	instance.header.version = config.api.getHeaderVersion()
+	instance.options = config.socketOptions
+	instance.reverse = config.reverse
+	instance.skipUnsubscribed = config.skipUnsubscribed
+	instance.subscriptions = make(map[string]bool)
+	instance.zapDomain = getZapDomain()
+	instance.authPolicy = newZapPolicy()
 	InitLogger()
 	return instance
 }
 
+// Set the server private/secret key.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+//
+// (2) This API should be called before start() API.
+//
+// (3) Publisher runs in secured mode only if this key is set, otherwise in
+// unsecured mode. Secured and unsecured publishers can run in the same
+// application.
+//
+// (4) Key is copied and the copy is wiped once it is applied on start, and on
+// Stop() and Close() APIs. Key should be set again before restarting
+// publisher.
+func (pubInstance *EZMQPublisher) SetServerPrivateKey(key []byte) EZMQErrorCode {
+	err := checkCurve("set server private key")
+	if nil != err {
+		return pubInstance.lastError.set(err)
+	}
+	if false == isValidKey(key) {
+		return pubInstance.lastError.set(newError(EZMQ_KEY_INVALID, "set server private key", nil))
+	}
+	pubInstance.mutex.Lock()
+	defer pubInstance.mutex.Unlock()
+	wipe(pubInstance.serverSecretKey)
+	pubInstance.serverSecretKey = copyKey(key)
+	pubInstance.secured = true
+	return pubInstance.lastError.set(nil)
+}
+
 // Starts PUB instance.
+//
+// Note:
+// (1) Start callback is called with the result, except when publisher is
+// already running.
 func (pubInstance *EZMQPublisher) Start() EZMQErrorCode {
+	_, created, err := pubInstance.start(false)
+	return pubInstance.notifyStart(created, pubInstance.lastError.set(err))
+}
+
+// Starts the publisher. If watchConnect is true and publisher connects to
+// subscriber, monitor of the socket is returned to wait for connection.
+// Returns true if socket is created, false if publisher is already running.
+func (pubInstance *EZMQPublisher) start(watchConnect bool) (*socketMonitor, bool, error) {
 	if nil == pubInstance.context {
-		logger.Error("Context is null")
-		return EZMQ_ERROR
+		return nil, false, newError(EZMQ_NOT_INITIALIZED, "start publisher", nil)
 	}
 
+	var monitor *socketMonitor
+	var created bool
 	pubInstance.mutex.Lock()
 	defer pubInstance.mutex.Unlock()
 	if nil == pubInstance.publisher {
 		var err error
-		pubInstance.publisher, err = instance.context.NewSocket(zmq.PUB)
+		pubInstance.publisher, err = pubInstance.context.NewSocket(zmq.XPUB)
+		if nil != err {
+			pubInstance.publisher = nil
+			return nil, false, newError(EZMQ_SOCKET_ERROR, "create publisher socket", err)
+		}
+		err = applySocketOptions(pubInstance.publisher, pubInstance.options)
+		if nil == err {
+			err = setEndpointOptions(pubInstance.publisher, pubInstance.endpoint)
+		}
 		if nil != err {
-			logger.Error("Publisher Socket creation failed")
+			pubInstance.publisher.Close()
+			pubInstance.publisher = nil
+			return nil, false, err
+		}
+		if true == pubInstance.secured {
+			// key is wiped after previous start
+			if len(pubInstance.serverSecretKey) != PUB_KEY_LENGTH {
+				pubInstance.publisher.Close()
+				pubInstance.publisher = nil
+				return nil, false, newError(EZMQ_KEY_INVALID, "set server secret key", nil)
+			}
+			err = pubInstance.publisher.ServerAuthCurve("", string(pubInstance.serverSecretKey[:]))
+			if nil != err {
+				pubInstance.publisher.Close()
+				pubInstance.publisher = nil
+				return nil, false, newError(EZMQ_KEY_INVALID, "set server secret key", err)
+			}
+		}
+		err = pubInstance.setPlainServer()
+		if nil == err {
+			err = pubInstance.startAuthentication()
+		}
+		if nil != err {
+			pubInstance.publisher.Close()
+			pubInstance.publisher = nil
+			return nil, false, err
 		}
-		var address string = getPubSocketAddress(pubInstance.port)
-		err = pubInstance.publisher.Bind(address)
+		var address string = pubInstance.endpoint.String()
+		pubInstance.monitor = startMonitor(pubInstance.publisher, false == pubInstance.reverse,
+			true == pubInstance.reverse && true == watchConnect, pubInstance.errorCallback, pubInstance.eventCallback)
+		err = attachSocket(pubInstance.publisher, pubInstance.endpoint, false == pubInstance.reverse, "publisher")
 		if nil != err {
-			logger.Error("Error while starting publisher")
+			pubInstance.monitor.close()
+			pubInstance.monitor = nil
+			pubInstance.stopAuthentication()
+			pubInstance.publisher.Close()
 			pubInstance.publisher = nil
-			return EZMQ_ERROR
+			return nil, false, err
 		}
-		logger.Debug("Publisher started", zap.String("address", address))
+		if true == pubInstance.reverse && true == watchConnect {
+			monitor = pubInstance.monitor
+		}
+		pubInstance.header.reset()
+		pubInstance.subscriptions = make(map[string]bool)
+		if nil != pubInstance.subscriptionCallback {
+			pubInstance.trackerStop = make(chan struct{})
+			go pubInstance.trackSubscriptions(pubInstance.trackerStop)
+		}
+		// key is applied on socket, it is not needed anymore
+		pubInstance.wipeKeys()
+		created = true
+		logger.Debug("Publisher started", zap.String("address", address), zap.Bool("secured", pubInstance.secured))
 	}
-	return EZMQ_OK
+	return monitor, created, nil
 }
 
-func getHeader(content EZMQContentType) []byte {
-
-	var ezmqHeader byte = 0x00
-	var version byte = 1
-	var contentType byte = (byte)(content)
-
-	version = (byte)(version << 2)
-	ezmqHeader = (byte)(ezmqHeader | version)
-	contentType = (byte)(contentType << 5)
-	ezmqHeader = (byte)(ezmqHeader | contentType)
-
-	header := [1]byte{ezmqHeader}
-	return header[:]
-}
-
-func (pubInstance *EZMQPublisher) publishInternal(topic string, ezmqMsg EZMQMessage) EZMQErrorCode {
+func (pubInstance *EZMQPublisher) publishMessage(ctx context.Context, topic string, ezmqMsg EZMQMessage) error {
 	if nil == ezmqMsg {
-		return EZMQ_ERROR
+		return newError(EZMQ_ERROR, "publish", nil)
+	}
+	// subscriptions are read on every publish, so that they are not queued
+	hasSubscribers, started := pubInstance.checkSubscribers(topic)
+	if true == started && false == hasSubscribers && true == pubInstance.skipUnsubscribed {
+		logger.Debug("No subscriber, skipped publishing", zap.String("topic", topic))
+		return nil
 	}
 	// form the EZMQ header
 	contentType := ezmqMsg.GetContentType()
-	header := getHeader(contentType)
+	codec, exists := getCodec(contentType)
+	if false == exists {
+		return newError(EZMQ_INVALID_CONTENT_TYPE, "publish", nil)
+	}
 
 	// form the EZMQ data
-	var byteEvent []byte = nil
-	var err error
-	if contentType == EZMQ_CONTENT_TYPE_PROTOBUF {
-		event := ezmqMsg.(Event)
-		byteEvent, err = proto.Marshal(&event)
-		if nil != err {
-			logger.Error("Error occured while marshalling proto event")
-			return EZMQ_ERROR
-		}
-	} else if contentType == EZMQ_CONTENT_TYPE_BYTEDATA {
-		ezmqByteData := ezmqMsg.(EZMQByteData)
-		byteEvent = ezmqByteData.GetByteData()
-	} else {
-		logger.Error("Not a supported messsage type")
-		return EZMQ_INVALID_CONTENT_TYPE
+	byteEvent, err := codec.marshal(ezmqMsg)
+	if nil != err {
+		return newError(EZMQ_SERIALIZATION_FAILED, "marshal data", err)
 	}
 
 	if nil == byteEvent {
-		logger.Error("nil byte event")
-		return EZMQ_ERROR
+		return newError(EZMQ_SERIALIZATION_FAILED, "marshal data", nil)
 	}
 
 	pubInstance.mutex.Lock()
 	defer pubInstance.mutex.Unlock()
 	if nil == pubInstance.publisher {
-		logger.Error("Publisher is nil")
-		return EZMQ_ERROR
+		return newError(EZMQ_NOT_STARTED, "publish", nil)
+	}
+	if nil != ctx.Err() {
+		return newContextError("publish", ctx.Err())
 	}
+	// set send timeout as per deadline [if any]
+	if timeout, exists := getTimeout(ctx); true == exists {
+		pubInstance.publisher.SetSndtimeo(timeout)
+		defer pubInstance.publisher.SetSndtimeo(-1)
+	}
+	header := pubInstance.header.next(topic, contentType)
+
 	// send topic [if any]
 	if topic != "" {
 		result, err := pubInstance.publisher.Send(topic, zmq.SNDMORE)
 		if nil != err {
-			logger.Error("Error while sending topic", zap.Int("Sent bytes", result))
-			return EZMQ_ERROR
+			logger.Debug("Error while sending topic", zap.Int("Sent bytes", result))
+			return newSendError(ctx, "send topic", err)
 		}
 	}
 
 	// send header
 	result, err := pubInstance.publisher.SendBytes(header, zmq.SNDMORE)
 	if nil != err {
-		logger.Error("Error while sending header", zap.Int("Sent bytes", result))
-		return EZMQ_ERROR
+		logger.Debug("Error while sending header", zap.Int("Sent bytes", result))
+		return newSendError(ctx, "send header", err)
 	}
 
 	// send data
 	result, err = pubInstance.publisher.SendBytes(byteEvent, 0)
 	if nil != err {
-		logger.Error("Error while publishing data", zap.Int("Sent bytes", result))
-		return EZMQ_ERROR
+		logger.Debug("Error while publishing data", zap.Int("Sent bytes", result))
+		return newSendError(ctx, "send data", err)
 	}
+	pubInstance.header.commit(topic)
 	logger.Debug("Published data")
-	return EZMQ_OK
+	return nil
 }
 
 // Publish events on the socket for subscribers.
 func (pubInstance *EZMQPublisher) Publish(ezmqMsg EZMQMessage) EZMQErrorCode {
-	if nil == pubInstance.publisher {
-		logger.Error("Publisher is null")
-		return EZMQ_ERROR
-	}
-	return pubInstance.publishInternal("", ezmqMsg)
+	return pubInstance.lastError.set(pubInstance.publishInternal(context.Background(), "", ezmqMsg))
 }
 
 // Publish events on a specific topic on socket for subscribers.
@@ -187,16 +311,16 @@ func (pubInstance *EZMQPublisher) Publish(ezmqMsg EZMQMessage) EZMQErrorCode {
 //
 // (2) Topic name can have letters [a-z, A-z], numerics [0-9] and special characters _ - / and .
 func (pubInstance *EZMQPublisher) PublishOnTopic(topic string, ezmqMsg EZMQMessage) EZMQErrorCode {
-	if nil == pubInstance.publisher {
-		return EZMQ_ERROR
-	}
+	return pubInstance.lastError.set(pubInstance.publishOnTopic(context.Background(), topic, ezmqMsg))
+}
 
+func (pubInstance *EZMQPublisher) publishOnTopic(ctx context.Context, topic string, ezmqMsg EZMQMessage) error {
 	//validate the topic
 	validTopic := sanitizeTopic(topic)
 	if validTopic == "" {
-		return EZMQ_INVALID_TOPIC
+		return newError(EZMQ_INVALID_TOPIC, "publish", nil)
 	}
-	return pubInstance.publishInternal(validTopic, ezmqMsg)
+	return pubInstance.publishInternal(ctx, validTopic, ezmqMsg)
 }
 
 // Publish an events on list of topics on socket for subscribers. On any of
@@ -208,43 +332,62 @@ func (pubInstance *EZMQPublisher) PublishOnTopic(topic string, ezmqMsg EZMQMessa
 //
 // (2) Topic name can have letters [a-z, A-z], numerics [0-9] and special characters _ - / and .
 func (pubInstance *EZMQPublisher) PublishOnTopicList(topicList List.List, ezmqMsg EZMQMessage) EZMQErrorCode {
+	return pubInstance.lastError.set(pubInstance.publishOnTopicList(context.Background(), topicList, ezmqMsg))
+}
+
+func (pubInstance *EZMQPublisher) publishOnTopicList(ctx context.Context, topicList List.List, ezmqMsg EZMQMessage) error {
 	if topicList.Len() == 0 {
-		return EZMQ_INVALID_TOPIC
+		return newError(EZMQ_INVALID_TOPIC, "publish", nil)
 	}
 	for topic := topicList.Front(); topic != nil; topic = topic.Next() {
-		result := pubInstance.PublishOnTopic(topic.Value.(string), ezmqMsg)
-		if result != EZMQ_OK {
-			return EZMQ_ERROR
+		err := pubInstance.publishOnTopic(ctx, topic.Value.(string), ezmqMsg)
+		if nil != err {
+			return err
 		}
 	}
-	return EZMQ_OK
+	return nil
 }
 
 // Stops PUB instance.
 func (pubInstance *EZMQPublisher) Stop() EZMQErrorCode {
+	return pubInstance.notifyStop(pubInstance.lastError.set(pubInstance.stop(context.Background())))
+}
+
+func (pubInstance *EZMQPublisher) stop(ctx context.Context) error {
 	pubInstance.mutex.Lock()
 	defer pubInstance.mutex.Unlock()
 
 	if nil == pubInstance.publisher {
-		logger.Error("Publisher is null")
-		return EZMQ_ERROR
+		return newError(EZMQ_NOT_STARTED, "stop publisher", nil)
+	}
+	if nil != pubInstance.trackerStop {
+		close(pubInstance.trackerStop)
+		pubInstance.trackerStop = nil
 	}
 	// Sync close
-	result := pubInstance.syncClose()
-	if result == EZMQ_OK {
+	err := pubInstance.syncClose(ctx)
+	if nil == err {
+		pubInstance.stopAuthentication()
 		pubInstance.publisher = nil
+		pubInstance.rotationEndpoint = nil
+		pubInstance.wipeKeys()
+		pubInstance.subscriptions = make(map[string]bool)
 		logger.Debug("Publisher Stopped")
 	}
-	return result
+	return err
 }
 
-// Get publisher port.
+// Get publisher port. For endpoints other than TCP, -1 is returned.
+//
+// Note:
+// (1) Use GetEndpoint() API which supports all transports.
 func (pubInstance *EZMQPublisher) GetPort() int {
 	return pubInstance.port
 }
 
-func getPubSocketAddress(port int) string {
-	return string(PUB_TCP_PREFIX) + strconv.Itoa(port)
+// Get endpoint on which publisher binds.
+func (pubInstance *EZMQPublisher) GetEndpoint() *EZMQEndpoint {
+	return pubInstance.endpoint
 }
 
 func sanitizeTopic(topic string) string {
@@ -268,56 +411,24 @@ func sanitizeTopic(topic string) string {
 	return topic
 }
 
-func getMonitorAddress() string {
-	var address string = "inproc://monitor-"
-	source := rand.NewSource(time.Now().UnixNano())
-	random := rand.New(source)
-	return address + strconv.Itoa(random.Intn(10000))
-}
-
-func (pubInstance *EZMQPublisher) syncClose() EZMQErrorCode {
-	var errMonitor error
-	var address string = getMonitorAddress()
-	errMonitor = pubInstance.publisher.Monitor(address, zmq.EVENT_CLOSED)
-	if errMonitor != nil {
-		logger.Info("Error in monitor")
-	}
-	socket, errMonitor := pubInstance.context.NewSocket(zmq.PAIR)
-	if errMonitor == nil {
-		errMonitor = socket.Connect(address)
-		if errMonitor != nil {
-			logger.Info("Pair socket connection failed")
-		}
-	} else {
-		logger.Info("Pair socket creation failed")
+func (pubInstance *EZMQPublisher) syncClose(ctx context.Context) error {
+	// pending messages should not be kept beyond the deadline
+	if timeout, exists := getTimeout(ctx); true == exists {
+		pubInstance.publisher.SetLinger(timeout)
 	}
 
 	//close the publisher socket
+	pubInstance.monitor.close()
 	err := pubInstance.publisher.Close()
 	if nil != err {
-		return EZMQ_ERROR
+		return newError(EZMQ_SOCKET_ERROR, "close publisher socket", err)
 	}
 	logger.Debug("Closed publisher socket")
 
-	if nil == socket || errMonitor != nil {
-		return EZMQ_OK
-	}
-	// set receive timeout
-	socket.SetRcvtimeo(time.Second)
-	for {
-		event, addr, value, err := socket.RecvEvent(0)
-		if err != nil {
-			logger.Error("Error while receiving Event")
-			socket.Close()
-			break
-		}
-		logger.Debug("Event received", zap.Int("eventType", int(event)), zap.String("address", addr),
-			zap.Int("Value", value))
-		if event == zmq.EVENT_CLOSED {
-			logger.Debug("Received EVENT_CLOSED from socket")
-			socket.Close()
-			break
-		}
+	// wait for socket to be closed till deadline [one second by default]
+	if nil != pubInstance.monitor {
+		pubInstance.monitor.waitStopped(ctx, time.Second)
+		pubInstance.monitor = nil
 	}
-	return EZMQ_OK
+	return nil
 }
@@ -0,0 +1,64 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+// Structure represents a message received by EZMQSubscriber.
+type EZMQReceivedMessage struct {
+	Topic       string
+	ContentType EZMQContentType
+	Message     EZMQMessage
+	Header      EZMQHeader
+}
+
+// Get channel on which received messages are delivered. Once channel is
+// created, messages are delivered only on it and subscriber callbacks are not
+// called.
+//
+// Note:
+// (1) Channel is created with given buffer size on first call. Further calls
+// return the same channel.
+//
+// (2) If channel is full, receiver waits till there is space in channel and
+// messages are queued on socket meanwhile.
+//
+// (3) Channel is not closed on Stop() and can be used again after restart.
+//
+// (4) This API should be called before start() API.
+func (subInstance *EZMQSubscriber) GetMessageChannel(bufferSize int) <-chan EZMQReceivedMessage {
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	if nil == subInstance.messageChan {
+		if bufferSize < 0 {
+			bufferSize = 0
+		}
+		subInstance.messageChan = make(chan EZMQReceivedMessage, bufferSize)
+	}
+	return subInstance.messageChan
+}
+
+// Send message on message channel. Returns false if receiver is stopped
+// while waiting.
+func (subInstance *EZMQSubscriber) deliver(message EZMQReceivedMessage, receiverStop chan struct{}) bool {
+	select {
+	case subInstance.messageChan <- message:
+		return true
+	case <-receiverStop:
+		logger.Debug("Receiver stopped while delivering message")
+		return false
+	}
+}
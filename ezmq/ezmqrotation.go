@@ -0,0 +1,157 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"go.uber.org/zap"
+)
+
+// Start rotation of server key. Publisher binds on the given endpoint with new
+// server private key, while the current endpoint keeps serving subscribers
+// with the current key. Subscribers switch to new endpoint and key using
+// RotateServerKey API during the grace window, which ends by
+// FinishKeyRotation API.
+//
+// Note:
+// (1) ZeroMQ applies security keys when an endpoint is bound and can not
+// accept two server keys on one endpoint, so new key is served on a different
+// endpoint. Next rotation can use the previous endpoint again. See "Server key
+// rotation" in README.
+//
+// (2) Events are published to subscribers of both the endpoints.
+//
+// (3) Rotation is canceled if publisher is stopped before it is finished.
+//
+// (4) Key is applied on socket and not kept by publisher. Key should be set
+// by SetServerPrivateKey API before restarting publisher.
+func (pubInstance *EZMQPublisher) StartKeyRotation(serverPrivateKey []byte, endpoint *EZMQEndpoint) EZMQErrorCode {
+	return pubInstance.lastError.set(pubInstance.startKeyRotation(serverPrivateKey, endpoint))
+}
+
+func (pubInstance *EZMQPublisher) startKeyRotation(serverPrivateKey []byte, endpoint *EZMQEndpoint) error {
+	if nil == endpoint {
+		return newError(EZMQ_INVALID_ENDPOINT, "start key rotation", nil)
+	}
+	if false == isValidKey(serverPrivateKey) {
+		return newError(EZMQ_KEY_INVALID, "start key rotation", nil)
+	}
+	pubInstance.mutex.Lock()
+	defer pubInstance.mutex.Unlock()
+	if nil == pubInstance.publisher {
+		return newError(EZMQ_NOT_STARTED, "start key rotation", nil)
+	}
+	if nil != pubInstance.rotationEndpoint {
+		return newError(EZMQ_ERROR, "start key rotation", nil)
+	}
+	err := pubInstance.publisher.ServerAuthCurve("", string(serverPrivateKey))
+	if nil != err {
+		return newError(EZMQ_KEY_INVALID, "set server secret key", err)
+	}
+	err = attachSocket(pubInstance.publisher, endpoint, false == pubInstance.reverse, "publisher")
+	if nil != err {
+		return err
+	}
+	pubInstance.rotationEndpoint = endpoint
+	pubInstance.secured = true
+	logger.Debug("Key rotation started", zap.String("Address", endpoint.String()))
+	return nil
+}
+
+// Finish rotation of server key started by StartKeyRotation API. Current
+// endpoint is closed along with its subscribers, and the rotated endpoint and
+// key become current.
+func (pubInstance *EZMQPublisher) FinishKeyRotation() EZMQErrorCode {
+	return pubInstance.lastError.set(pubInstance.finishKeyRotation())
+}
+
+func (pubInstance *EZMQPublisher) finishKeyRotation() error {
+	pubInstance.mutex.Lock()
+	defer pubInstance.mutex.Unlock()
+	if nil == pubInstance.publisher {
+		return newError(EZMQ_NOT_STARTED, "finish key rotation", nil)
+	}
+	if nil == pubInstance.rotationEndpoint {
+		return newError(EZMQ_ERROR, "finish key rotation", nil)
+	}
+	var bind bool = false == pubInstance.reverse
+	if true == bind {
+		pubInstance.monitor.expectUnbind()
+	}
+	err := detachSocket(pubInstance.publisher, pubInstance.endpoint, bind, "publisher")
+	if nil != err {
+		return err
+	}
+	pubInstance.endpoint = pubInstance.rotationEndpoint
+	pubInstance.port = pubInstance.endpoint.GetPort()
+	pubInstance.rotationEndpoint = nil
+	logger.Debug("Key rotation finished", zap.String("Address", pubInstance.endpoint.String()))
+	return nil
+}
+
+// Switch to new server public key and endpoint of publisher, without
+// stopping subscriber. Subscriptions are kept as the same socket connects to
+// new endpoint and then disconnects from the current one.
+//
+// Note:
+// (1) Events published while connection to new endpoint is being established
+// can be missed, same as on reconnection.
+//
+// (2) Endpoints added by SubscribeWithIPPort API are not rotated.
+func (subInstance *EZMQSubscriber) RotateServerKey(serverPublicKey []byte, endpoint *EZMQEndpoint) EZMQErrorCode {
+	return subInstance.lastError.set(subInstance.rotateServerKey(serverPublicKey, endpoint))
+}
+
+func (subInstance *EZMQSubscriber) rotateServerKey(serverPublicKey []byte, endpoint *EZMQEndpoint) error {
+	if nil == endpoint {
+		return newError(EZMQ_INVALID_ENDPOINT, "rotate server key", nil)
+	}
+	if false == isValidKey(serverPublicKey) {
+		return newError(EZMQ_KEY_INVALID, "rotate server key", nil)
+	}
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	if nil == subInstance.subscriber {
+		return newError(EZMQ_NOT_STARTED, "rotate server key", nil)
+	}
+	if false == subInstance.secured {
+		return newError(EZMQ_KEY_INVALID, "rotate server key", nil)
+	}
+	err := subInstance.applyClientKeys(serverPublicKey)
+	if nil != err {
+		return err
+	}
+	err = attachSocket(subInstance.subscriber, endpoint, subInstance.reverse, "subscriber")
+	if nil != err {
+		// restore current key for the endpoints attached later
+		subInstance.applyClientKeys(subInstance.serverPublicKey)
+		return err
+	}
+	if true == subInstance.reverse {
+		subInstance.monitor.expectUnbind()
+	}
+	err = detachSocket(subInstance.subscriber, subInstance.endpoint, subInstance.reverse, "subscriber")
+	if nil != err {
+		logger.Error("Detach from previous endpoint failed", zap.Error(err))
+	}
+	subInstance.endpoint = endpoint
+	subInstance.ip = endpoint.GetHost()
+	subInstance.port = endpoint.GetPort()
+	subInstance.serverPublicKey = copyKey(serverPublicKey)
+	logger.Debug("Server key rotated", zap.String("Address", endpoint.String()))
+	return nil
+}
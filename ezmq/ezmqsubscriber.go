@@ -1,13 +1,11 @@
-// +build unsecure
-
 package ezmq
 
 import (
-	proto "github.com/golang/protobuf/proto"
 	zmq "github.com/pebbe/zmq4"
 	"go.uber.org/zap"
 
 	List "container/list"
+	"context"
 	"math/rand"
 	"strconv"
 	"strings"
@@ -19,6 +17,9 @@ import (
 const SUB_TCP_PREFIX = "tcp://"
 const INPROC_PREFIX = "inproc://shutdown-"
 
+// Subscriber key length
+const SUB_KEY_LENGTH = 40
+
 // Callback to get all the subscribed events.
 type EZMQSubCB func(event EZMQMessage)
 
@@ -27,31 +28,72 @@ type EZMQSubTopicCB func(topic string, event EZMQMessage)
 
 // Structure represents EZMQSubscriber.
 type EZMQSubscriber struct {
-	ip               string
-	port             int
-	subCallback      EZMQSubCB
-	subTopicCallback EZMQSubTopicCB
-	mutex            *sync.Mutex
-
-	context        *zmq.Context
-	subscriber     *zmq.Socket
-	shutdownServer *zmq.Socket
-	shutdownClient *zmq.Socket
-	poller         *zmq.Poller
-	shutdownChan   chan string
+	ip                string
+	port              int
+	endpoint          *EZMQEndpoint
+	subCallback       EZMQSubCB
+	subTopicCallback  EZMQSubTopicCB
+	subHeaderCallback EZMQSubHeaderCB
+	lossCallback      EZMQLossCB
+	startCallback     EZMQStartCB
+	stopCallback      EZMQStopCB
+	errorCallback     EZMQErrorCB
+	eventCallback     EZMQEventCB
+	lossDetector      *lossDetector
+	topicFilter       *topicFilter
+	topicRouter       *topicRouter
+	messageChan       chan EZMQReceivedMessage
+	receiverStop      chan struct{}
+	options           []socketOption
+	reverse           bool
+	username          string
+	password          []byte
+	lastError         errorHolder
+	mutex             *sync.Mutex
+	serverPublicKey   []byte
+	clientPublicKey   []byte
+	clientSecretKey   []byte
+	secured           bool
+	context           *zmq.Context
+	subscriber        *zmq.Socket
+	monitor           *socketMonitor
+	shutdownServer    *zmq.Socket
+	shutdownClient    *zmq.Socket
+	poller            *zmq.Poller
+	shutdownChan      chan string
 
 	isReceiverStarted bool
 }
 
-// Constructs EZMQSubscriber.
-func GetEZMQSubscriber(ip string, port int, subCallback EZMQSubCB, subTopicCallback EZMQSubTopicCB) *EZMQSubscriber {
+// Constructs EZMQSubscriber which connects to given IP and port.
+//
+// Note:
+// (1) Subscriber is created on the EZMQAPI instance given by WithAPI option,
+// otherwise on the instance returned by GetInstance().
+func GetEZMQSubscriber(ip string, port int, subCallback EZMQSubCB, subTopicCallback EZMQSubTopicCB,
+	options ...EZMQOption) *EZMQSubscriber {
+	return GetEZMQSubscriberWithEndpoint(GetEZMQTCPEndpoint(ip, port), subCallback, subTopicCallback, options...)
+}
+
+// Constructs EZMQSubscriber which connects to given endpoint.
+//
+// Note:
+// (1) Subscriber is created on the EZMQAPI instance given by WithAPI option,
+// otherwise on the instance returned by GetInstance().
+func GetEZMQSubscriberWithEndpoint(endpoint *EZMQEndpoint, subCallback EZMQSubCB, subTopicCallback EZMQSubTopicCB,
+	options ...EZMQOption) *EZMQSubscriber {
+	if nil == endpoint {
+		return nil
+	}
 	var instance *EZMQSubscriber
+	var config *ezmqOptions = getOptions(options)
 	instance = &EZMQSubscriber{}
-	instance.ip = ip
-	instance.port = port
+	instance.endpoint = endpoint
+	instance.ip = endpoint.GetHost()
+	instance.port = endpoint.GetPort()
 	instance.subCallback = subCallback
 	instance.subTopicCallback = subTopicCallback
-	instance.context = GetInstance().GetContext()
+	instance.context = config.api.GetContext()
 	InitLogger()
 	if nil == instance.context {
 		logger.Error("Context is null")
@@ -64,15 +106,20 @@ func GetEZMQSubscriber(ip string, port int, subCallback EZMQSubCB, subTopicCallb
 	instance.shutdownChan = nil
 	instance.isReceiverStarted = false
 	instance.mutex = &sync.Mutex{}
+	instance.lossDetector = newLossDetector()
+	instance.topicFilter = newTopicFilter()
+	instance.topicRouter = newTopicRouter()
+	instance.options = config.socketOptions
+	instance.reverse = config.reverse
 	return instance
 }
 
-func parseSocketData(subInstance *EZMQSubscriber) {
+// Parse the received message and deliver it to callbacks. If message channel
+// is set, message to be sent on it is returned instead.
+func parseSocketData(subInstance *EZMQSubscriber) *EZMQReceivedMessage {
 	var frame1 []byte
 	var frame2 []byte
 	var frame3 []byte
-	var event Event
-	var ezmqByteData EZMQByteData
 	var isTopic bool = false
 	var topic string
 	var more bool = false
@@ -82,7 +129,7 @@ func parseSocketData(subInstance *EZMQSubscriber) {
 	defer subInstance.mutex.Unlock()
 	if nil == subInstance.subscriber {
 		logger.Error("subscriber is null")
-		return
+		return nil
 	}
 	frame1, err = subInstance.subscriber.RecvBytes(0)
 	if err == nil {
@@ -106,38 +153,62 @@ func parseSocketData(subInstance *EZMQSubscriber) {
 		if strings.HasSuffix(topic, "/") {
 			topic = topic[:len(topic)-len("/")]
 		}
+		// prefix subscribed for wildcard topic can deliver other topics
+		if nil == err && false == subInstance.topicFilter.match(topic) {
+			return nil
+		}
 	}
 
-	//Parse header
-	ezmqHeader := frame2[0]
-	var contentType byte = (ezmqHeader >> 5)
+	if nil != err || nil == frame2 {
+		logger.Error("Error while receiving data")
+		return nil
+	}
 
-	// Parse the data
-	if EZMQ_CONTENT_TYPE_PROTOBUF == contentType {
-		//change byte array to Event
-		err = proto.Unmarshal(frame3, &event)
-		if nil != err {
-			logger.Error("Error in unmarshalling data")
-		}
-		if isTopic {
-			subInstance.subTopicCallback(topic, event)
-		} else {
-			subInstance.subCallback(event)
+	//Parse header
+	header, result := parseHeader(frame2)
+	if result != EZMQ_OK {
+		if EZMQ_INVALID_VERSION == result && nil != subInstance.errorCallback {
+			subInstance.errorCallback(EZMQ_INVALID_VERSION)
 		}
-	} else if EZMQ_CONTENT_TYPE_BYTEDATA == contentType {
-		ezmqByteData.ByteData = frame3
-		if isTopic {
-			subInstance.subTopicCallback(topic, ezmqByteData)
-		} else {
-			subInstance.subCallback(ezmqByteData)
+		return nil
+	}
+	if nil != subInstance.lossCallback {
+		report, detected := subInstance.lossDetector.check(topic, header)
+		if detected {
+			subInstance.lossCallback(report)
 		}
+	}
+	codec, exists := getCodec(header.ContentType)
+	if false == exists {
+		logger.Error("Not a supported type", zap.Int("contentType", int(header.ContentType)))
+		return nil
+	}
+
+	// Parse the data
+	ezmqMsg, err := codec.unmarshal(frame3)
+	if nil != err {
+		logger.Error("Error in unmarshalling data", zap.Error(err))
+		return nil
+	}
+	if nil != subInstance.messageChan {
+		return &EZMQReceivedMessage{Topic: topic, ContentType: header.ContentType, Message: ezmqMsg, Header: header}
+	}
+	if isTopic && subInstance.topicRouter.route(topic, ezmqMsg) {
+		return nil
+	}
+	if nil != subInstance.subHeaderCallback {
+		subInstance.subHeaderCallback(topic, header, ezmqMsg)
+	} else if isTopic {
+		subInstance.subTopicCallback(topic, ezmqMsg)
 	} else {
-		logger.Error("Not a supported type")
+		subInstance.subCallback(ezmqMsg)
 	}
+	return nil
 }
 
-func receive(subInstance *EZMQSubscriber) {
+func receive(subInstance *EZMQSubscriber, receiverStop chan struct{}) {
 	var sockets []zmq.Polled
+	var message *EZMQReceivedMessage
 	var socket zmq.Polled
 	var soc *zmq.Socket
 	var err error
@@ -148,7 +219,10 @@ func receive(subInstance *EZMQSubscriber) {
 			for _, socket = range sockets {
 				switch soc = socket.Socket; soc {
 				case subInstance.subscriber:
-					parseSocketData(subInstance)
+					message = parseSocketData(subInstance)
+					if nil != message && false == subInstance.deliver(*message, receiverStop) {
+						goto End
+					}
 				case subInstance.shutdownClient:
 					logger.Debug("Received shut down request")
 					goto End
@@ -163,57 +237,154 @@ End:
 	}
 }
 
+// Set the security keys of client/its own.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format <br>
+//
+// (2) This API should be called before start() API.
+//
+// (3) Subscriber runs in secured mode only if client keys and server public key
+// are set, otherwise in unsecured mode. Secured and unsecured subscribers can
+// run in the same application.
+//
+// (4) Keys are copied and the copy of private key is wiped once it is applied,
+// and on Stop() and Close() APIs. Keys should be set again before restarting
+// subscriber.
+func (subInstance *EZMQSubscriber) SetClientKeys(clientPrivateKey []byte, clientPublicKey []byte) EZMQErrorCode {
+	err := checkCurve("set client keys")
+	if nil != err {
+		return subInstance.lastError.set(err)
+	}
+	if false == isValidKey(clientPrivateKey) || false == isValidKey(clientPublicKey) {
+		return subInstance.lastError.set(newError(EZMQ_KEY_INVALID, "set client keys", nil))
+	}
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	wipe(subInstance.clientSecretKey)
+	subInstance.clientSecretKey = copyKey(clientPrivateKey)
+	subInstance.clientPublicKey = copyKey(clientPublicKey)
+	subInstance.secured = true
+	return subInstance.lastError.set(nil)
+}
+
+// Set the server public key.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format <br>
+//
+// (2) This API should be called before start() API.
+//
+// (3) If using the following API in secured mode:
+//
+//     SubscribeWithIPPort(ip string, port int, topic string) EZMQErrorCode
+//     SetServerPublicKey API needs to be called before that.
+func (subInstance *EZMQSubscriber) SetServerPublicKey(key []byte) EZMQErrorCode {
+	err := checkCurve("set server public key")
+	if nil != err {
+		return subInstance.lastError.set(err)
+	}
+	if false == isValidKey(key) {
+		return subInstance.lastError.set(newError(EZMQ_KEY_INVALID, "set server public key", nil))
+	}
+	subInstance.serverPublicKey = copyKey(key)
+	return subInstance.lastError.set(nil)
+}
+
 // Starts SUB instance.
 func (subInstance *EZMQSubscriber) Start() EZMQErrorCode {
+	_, created, err := subInstance.start(false)
+	return subInstance.notifyStart(created, subInstance.lastError.set(err))
+}
+
+// Starts the subscriber. If watchConnect is true and subscriber connects to
+// publisher, monitor of the socket is returned to wait for connection.
+// Returns true if socket is created, false if subscriber is already running.
+func (subInstance *EZMQSubscriber) start(watchConnect bool) (*socketMonitor, bool, error) {
 	if nil == subInstance.context {
-		logger.Error("Context is null")
-		return EZMQ_ERROR
+		return nil, false, newError(EZMQ_NOT_INITIALIZED, "start subscriber", nil)
 	}
 
 	var err error
+	var monitor *socketMonitor
+	var created bool
 	var address = getInProcUniqueAddress()
 	subInstance.mutex.Lock()
 	defer subInstance.mutex.Unlock()
 	if nil == subInstance.shutdownServer {
-		subInstance.shutdownServer, err = zmq.NewSocket(zmq.PAIR)
+		subInstance.shutdownServer, err = subInstance.context.NewSocket(zmq.PAIR)
 		if nil != err {
-			logger.Error("shutdownServer Socket creation failed")
-			return EZMQ_ERROR
+			subInstance.shutdownServer = nil
+			return nil, false, newError(EZMQ_SOCKET_ERROR, "create shutdown socket", err)
 		}
 		err = subInstance.shutdownServer.Bind(address)
 		if nil != err {
-			logger.Error("Error while binding shutdownServer")
+			subInstance.shutdownServer.Close()
 			subInstance.shutdownServer = nil
-			return EZMQ_ERROR
+			return nil, false, newError(EZMQ_SOCKET_ERROR, "bind shutdown socket", err)
 		}
 	}
 
 	if nil == subInstance.shutdownClient {
-		subInstance.shutdownClient, err = zmq.NewSocket(zmq.PAIR)
+		subInstance.shutdownClient, err = subInstance.context.NewSocket(zmq.PAIR)
 		if nil != err {
-			logger.Error("shutdownClient Socket creation failed")
-			return EZMQ_ERROR
+			subInstance.shutdownClient = nil
+			return nil, false, newError(EZMQ_SOCKET_ERROR, "create shutdown socket", err)
 		}
 		err = subInstance.shutdownClient.Connect(address)
 		if nil != err {
-			logger.Error("shutdownClient Socket connect failed")
-			return EZMQ_ERROR
+			return nil, false, newError(EZMQ_SOCKET_ERROR, "connect shutdown socket", err)
 		}
 		logger.Debug("shutdownClient subscriber", zap.String("Address", address))
 	}
 
 	if nil == subInstance.subscriber {
-		subInstance.subscriber, err = zmq.NewSocket(zmq.SUB)
+		subInstance.subscriber, err = subInstance.context.NewSocket(zmq.SUB)
+		if nil != err {
+			subInstance.subscriber = nil
+			return nil, false, newError(EZMQ_SOCKET_ERROR, "create subscriber socket", err)
+		}
+		err = applySocketOptions(subInstance.subscriber, subInstance.options)
+		if nil == err {
+			err = setEndpointOptions(subInstance.subscriber, subInstance.endpoint)
+		}
 		if nil != err {
-			logger.Error("Subscriber Socket creation failed")
-			return EZMQ_ERROR
+			subInstance.subscriber.Close()
+			subInstance.subscriber = nil
+			return nil, false, err
 		}
-		address = getSubSocketAddress(subInstance.ip, subInstance.port)
-		err = subInstance.subscriber.Connect(address)
+		//set keys
+		if true == subInstance.secured && len(subInstance.serverPublicKey) == SUB_KEY_LENGTH {
+			err = subInstance.applyClientKeys(subInstance.serverPublicKey)
+			if nil != err {
+				subInstance.subscriber.Close()
+				subInstance.subscriber = nil
+				return nil, false, err
+			}
+		}
+		err = subInstance.setPlainClient()
 		if nil != err {
-			logger.Error("Subscriber Socket connect failed")
-			return EZMQ_ERROR
+			subInstance.subscriber.Close()
+			subInstance.subscriber = nil
+			return nil, false, err
 		}
+		address = subInstance.endpoint.String()
+		subInstance.monitor = startMonitor(subInstance.subscriber, subInstance.reverse,
+			false == subInstance.reverse && true == watchConnect, subInstance.errorCallback, subInstance.eventCallback)
+		err = attachSocket(subInstance.subscriber, subInstance.endpoint, subInstance.reverse, "subscriber")
+		if nil != err {
+			if true == subInstance.reverse {
+				subInstance.monitor.close()
+				subInstance.monitor = nil
+				subInstance.subscriber.Close()
+				subInstance.subscriber = nil
+			}
+			return nil, false, err
+		}
+		if false == subInstance.reverse && true == watchConnect {
+			monitor = subInstance.monitor
+		}
+		created = true
 		logger.Debug("Starting subscriber", zap.String("Address", address))
 	}
 
@@ -226,43 +397,57 @@ func (subInstance *EZMQSubscriber) Start() EZMQErrorCode {
 	//call a go routine [new thread] for receiver
 	if false == subInstance.isReceiverStarted {
 		subInstance.isReceiverStarted = true
-		go receive(subInstance)
+		subInstance.receiverStop = make(chan struct{})
+		go receive(subInstance, subInstance.receiverStop)
 	}
-	return EZMQ_OK
+	return monitor, created, nil
 }
 
-func (subInstance *EZMQSubscriber) subscribeInternal(topic string) EZMQErrorCode {
+// Subscribe for the given prefix on ZeroMQ socket and add topic to filter.
+func (subInstance *EZMQSubscriber) subscribeInternal(prefix string, topic string) error {
 	subInstance.mutex.Lock()
 	defer subInstance.mutex.Unlock()
 
 	if nil != subInstance.subscriber {
-		err := subInstance.subscriber.SetSubscribe(topic)
+		err := subInstance.subscriber.SetSubscribe(prefix)
 		if nil != err {
-			logger.Error("subscribeInternal error occured")
-			return EZMQ_ERROR
+			return newError(EZMQ_SOCKET_ERROR, "subscribe", err)
 		}
 	} else {
-		logger.Error("subscriber is null")
-		return EZMQ_ERROR
+		return newError(EZMQ_NOT_STARTED, "subscribe", nil)
 	}
+	subInstance.topicFilter.add(topic)
 	logger.Debug("subscribed for events")
-	return EZMQ_OK
+	return nil
 }
 
 // Subscribe for event/messages.
 func (subInstance *EZMQSubscriber) Subscribe() EZMQErrorCode {
-	return subInstance.subscribeInternal("")
+	return subInstance.lastError.set(subInstance.subscribeInternal("", ""))
 }
 
 // Subscribe for event/messages on a particular topic.
+//
+// Note:
+// (1) Topic can have wildcards as levels. + matches exactly one level and
+// # matches any number of levels, it should be the last level.
+// For example: home/+/temperature or home/#
+//
+// (2) Wildcard topics are matched by subscriber. Topic levels before the first
+// wildcard are subscribed on publisher, so that other topics are still
+// filtered by publisher.
 func (subInstance *EZMQSubscriber) SubscribeForTopic(topic string) EZMQErrorCode {
+	return subInstance.lastError.set(subInstance.subscribeForTopic(topic))
+}
+
+func (subInstance *EZMQSubscriber) subscribeForTopic(topic string) error {
 	//validate the topic
-	validTopic := sanitizeTopic(topic)
+	prefix, validTopic := getSubscriptionTopic(topic)
 	if validTopic == "" {
-		return EZMQ_INVALID_TOPIC
+		return newError(EZMQ_INVALID_TOPIC, "subscribe", nil)
 	}
 	logger.Debug("subscribing for events", zap.String("Topic", validTopic))
-	return subInstance.subscribeInternal(validTopic)
+	return subInstance.subscribeInternal(prefix, validTopic)
 }
 
 // Subscribe for event/messages on given list of topics. On any of the topic
@@ -275,15 +460,15 @@ func (subInstance *EZMQSubscriber) SubscribeForTopic(topic string) EZMQErrorCode
 // (2) Topic name can have letters [a-z, A-z], numerics [0-9] and special characters _ - / and .
 func (subInstance *EZMQSubscriber) SubscribeForTopicList(topicList List.List) EZMQErrorCode {
 	if topicList.Len() == 0 {
-		return EZMQ_INVALID_TOPIC
+		return subInstance.lastError.set(newError(EZMQ_INVALID_TOPIC, "subscribe", nil))
 	}
 	for topic := topicList.Front(); topic != nil; topic = topic.Next() {
-		result := subInstance.SubscribeForTopic(topic.Value.(string))
-		if result != EZMQ_OK {
-			return result
+		err := subInstance.subscribeForTopic(topic.Value.(string))
+		if nil != err {
+			return subInstance.lastError.set(err)
 		}
 	}
-	return EZMQ_OK
+	return subInstance.lastError.set(nil)
 }
 
 // Subscribe for event/messages from given IP:Port on the given topic.
@@ -298,55 +483,89 @@ func (subInstance *EZMQSubscriber) SubscribeForTopicList(topicList List.List) EZ
 // (4) Topic name can have letters [a-z, A-z], numeric [0-9] and special characters _ - / and .
 //
 // (5) Topic will be appended with forward slash [/] in case, if application has not appended it.
+//
+// (6) If using in secured mode: Call setServerPublicKey API with target server public key before calling this API.
 func (subInstance *EZMQSubscriber) SubscribeWithIPPort(ip string, port int, topic string) EZMQErrorCode {
 	if port < 0 {
-		return EZMQ_ERROR
+		return subInstance.lastError.set(newError(EZMQ_ERROR, "subscribe with ip port", nil))
+	}
+	return subInstance.lastError.set(subInstance.subscribeWithEndpoint(GetEZMQTCPEndpoint(ip, port), topic))
+}
+
+// Subscribe for event/messages from given endpoint on the given topic.
+//
+// Note:
+// (1) It will be using same Subscriber socket for connecting to given endpoint.
+//
+// (2) In reverse topology, subscriber binds on the given endpoint.
+//
+// (3) Other notes of SubscribeWithIPPort API apply to this API as well.
+func (subInstance *EZMQSubscriber) SubscribeWithEndpoint(endpoint *EZMQEndpoint, topic string) EZMQErrorCode {
+	if nil == endpoint {
+		return subInstance.lastError.set(newError(EZMQ_INVALID_ENDPOINT, "subscribe with endpoint", nil))
 	}
+	return subInstance.lastError.set(subInstance.subscribeWithEndpoint(endpoint, topic))
+}
+
+func (subInstance *EZMQSubscriber) subscribeWithEndpoint(endpoint *EZMQEndpoint, topic string) error {
 	//validate the topic
-	validTopic := sanitizeTopic(topic)
+	prefix, validTopic := getSubscriptionTopic(topic)
 	if validTopic == "" {
-		return EZMQ_INVALID_TOPIC
+		return newError(EZMQ_INVALID_TOPIC, "subscribe with endpoint", nil)
 	}
 	subInstance.mutex.Lock()
 	defer subInstance.mutex.Unlock()
 	if nil == subInstance.subscriber {
-		logger.Error("subscriber is null")
-		return EZMQ_ERROR
+		return newError(EZMQ_NOT_STARTED, "subscribe with endpoint", nil)
+	}
+	var err error
+	//set keys
+	if true == subInstance.secured && len(subInstance.serverPublicKey) == SUB_KEY_LENGTH {
+		err = subInstance.applyClientKeys(subInstance.serverPublicKey)
+		if nil != err {
+			return err
+		}
 	}
-	address := getSubSocketAddress(ip, port)
-	err := subInstance.subscriber.Connect(address)
+	err = setEndpointOptions(subInstance.subscriber, endpoint)
 	if nil != err {
-		logger.Error("Subscriber Socket connect failed")
-		return EZMQ_ERROR
+		return err
 	}
-	logger.Debug("Connected subscriber", zap.String("Address", address))
-	err = subInstance.subscriber.SetSubscribe(validTopic)
+	err = attachSocket(subInstance.subscriber, endpoint, subInstance.reverse, "subscriber")
 	if nil != err {
-		logger.Error("SubscribeWithIPPort error occured")
-		return EZMQ_ERROR
+		return err
 	}
+	logger.Debug("Connected subscriber", zap.String("Address", endpoint.String()))
+	err = subInstance.subscriber.SetSubscribe(prefix)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "subscribe", err)
+	}
+	subInstance.topicFilter.add(validTopic)
 	logger.Debug("subscribed for events with ip ports", zap.String("Topic", validTopic))
-	return EZMQ_OK
+	return nil
 }
 
-func (subInstance *EZMQSubscriber) unSubscribeInternal(topic string) EZMQErrorCode {
+// Un-subscribe the given prefix on ZeroMQ socket and remove topic from filter.
+func (subInstance *EZMQSubscriber) unSubscribeInternal(prefix string, topic string) error {
 	subInstance.mutex.Lock()
 	defer subInstance.mutex.Unlock()
-	if nil != subInstance.subscriber {
-		err := subInstance.subscriber.SetUnsubscribe(topic)
-		if nil != err {
-			logger.Error("subscriber is null")
-			return EZMQ_ERROR
-		}
-	} else {
-		return EZMQ_ERROR
+	if nil == subInstance.subscriber {
+		return newError(EZMQ_NOT_STARTED, "unsubscribe", nil)
 	}
-	return EZMQ_OK
+	if false == subInstance.topicFilter.remove(topic) {
+		// not subscribed, ZeroMQ subscription of prefix may be in use
+		return nil
+	}
+	err := subInstance.subscriber.SetUnsubscribe(prefix)
+	if nil != err {
+		return newError(EZMQ_SOCKET_ERROR, "unsubscribe", err)
+	}
+	subInstance.lossDetector.forget(topic)
+	return nil
 }
 
 // Un-subscribe all the events from publisher.
 func (subInstance *EZMQSubscriber) UnSubscribe() EZMQErrorCode {
-	return subInstance.unSubscribeInternal("")
+	return subInstance.lastError.set(subInstance.unSubscribeInternal("", ""))
 }
 
 // Un-subscribe specific topic events.
@@ -356,13 +575,17 @@ func (subInstance *EZMQSubscriber) UnSubscribe() EZMQErrorCode {
 //
 // (2) Topic name can have letters [a-z, A-z], numerics [0-9] and special characters _ - / and .
 func (subInstance *EZMQSubscriber) UnSubscribeForTopic(topic string) EZMQErrorCode {
+	return subInstance.lastError.set(subInstance.unSubscribeForTopic(topic))
+}
+
+func (subInstance *EZMQSubscriber) unSubscribeForTopic(topic string) error {
 	//validate the topic
-	validTopic := sanitizeTopic(topic)
+	prefix, validTopic := getSubscriptionTopic(topic)
 	if validTopic == "" {
-		return EZMQ_INVALID_TOPIC
+		return newError(EZMQ_INVALID_TOPIC, "unsubscribe", nil)
 	}
 	logger.Debug("Unsubscribe for events", zap.String("Topic", validTopic))
-	return subInstance.unSubscribeInternal(validTopic)
+	return subInstance.unSubscribeInternal(prefix, validTopic)
 }
 
 // Un-subscribe event/messages on given list of topics. On any of the topic
@@ -375,38 +598,46 @@ func (subInstance *EZMQSubscriber) UnSubscribeForTopic(topic string) EZMQErrorCo
 // (2) Topic name can have letters [a-z, A-z], numerics [0-9] and special characters _ - / and .
 func (subInstance *EZMQSubscriber) UnSubscribeForTopicList(topicList List.List) EZMQErrorCode {
 	if topicList.Len() == 0 {
-		return EZMQ_INVALID_TOPIC
+		return subInstance.lastError.set(newError(EZMQ_INVALID_TOPIC, "unsubscribe", nil))
 	}
 	for topic := topicList.Front(); topic != nil; topic = topic.Next() {
-		result := subInstance.UnSubscribeForTopic(topic.Value.(string))
-		if result != EZMQ_OK {
-			return result
+		err := subInstance.unSubscribeForTopic(topic.Value.(string))
+		if nil != err {
+			return subInstance.lastError.set(err)
 		}
 	}
-	return EZMQ_OK
+	return subInstance.lastError.set(nil)
 }
 
 // Stops SUB instance.
 func (subInstance *EZMQSubscriber) Stop() EZMQErrorCode {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return subInstance.notifyStop(subInstance.lastError.set(subInstance.stop(ctx)))
+}
+
+func (subInstance *EZMQSubscriber) stop(ctx context.Context) error {
 	subInstance.mutex.Lock()
 	defer subInstance.mutex.Unlock()
 	if nil != subInstance.shutdownServer && subInstance.isReceiverStarted == true {
-		subInstance.shutdownChan = make(chan string)
-		timeout := make(chan bool, 1)
-		go func() {
-			time.Sleep(1 * time.Second)
-			timeout <- true
-		}()
+		shutdownChan := make(chan string, 1)
+		subInstance.shutdownChan = shutdownChan
+		subInstance.isReceiverStarted = false
+		close(subInstance.receiverStop)
+		subInstance.receiverStop = nil
 		result, err := subInstance.shutdownServer.Send("shutdown", 0)
 		if nil != err {
 			logger.Error("Error while sending event on shutdownServer", zap.Int("result: ", result))
 		} else {
+			// receiver may be waiting for the lock to deliver a message
+			subInstance.mutex.Unlock()
 			select {
-			case <-subInstance.shutdownChan:
+			case <-shutdownChan:
 				logger.Debug("Received success shutdown signal")
-			case <-timeout:
+			case <-ctx.Done():
 				logger.Debug("Timeout occured for shutdown socket")
 			}
+			subInstance.mutex.Lock()
 		}
 	}
 
@@ -418,24 +649,22 @@ func (subInstance *EZMQSubscriber) Stop() EZMQErrorCode {
 	if nil != subInstance.shutdownClient {
 		err := subInstance.shutdownClient.Close()
 		if nil != err {
-			logger.Error("Error while closing shutdownClient socket")
-			return EZMQ_ERROR
+			return newError(EZMQ_SOCKET_ERROR, "close shutdown socket", err)
 		}
 	}
 
 	if nil != subInstance.shutdownServer {
 		err := subInstance.shutdownServer.Close()
 		if nil != err {
-			logger.Error("Error while closing shutdownServer socket")
-			return EZMQ_ERROR
+			return newError(EZMQ_SOCKET_ERROR, "close shutdown socket", err)
 		}
 	}
 
 	if nil != subInstance.subscriber {
+		subInstance.monitor.close()
 		err := subInstance.subscriber.Close()
 		if nil != err {
-			logger.Error("Error while closing subscriber")
-			return EZMQ_ERROR
+			return newError(EZMQ_SOCKET_ERROR, "close subscriber socket", err)
 		}
 	}
 
@@ -443,24 +672,37 @@ func (subInstance *EZMQSubscriber) Stop() EZMQErrorCode {
 	subInstance.shutdownClient = nil
 	subInstance.shutdownServer = nil
 	subInstance.subscriber = nil
+	subInstance.monitor = nil
 	subInstance.shutdownChan = nil
 	subInstance.isReceiverStarted = false
+	subInstance.lossDetector.reset()
+	subInstance.topicFilter.reset()
+	subInstance.wipeKeys()
 	logger.Debug("Subscriber stopped")
-	return EZMQ_OK
+	return nil
 }
 
-// Get Ip of publisher to which subscribed.
+// Get Ip of publisher to which subscribed. For endpoints other than TCP, empty
+// string is returned.
+//
+// Note:
+// (1) Use GetEndpoint() API which supports all transports.
 func (subInstance *EZMQSubscriber) GetIP() string {
 	return subInstance.ip
 }
 
-// Get Port of publisher to which subscribed.
+// Get Port of publisher to which subscribed. For endpoints other than TCP, -1
+// is returned.
+//
+// Note:
+// (1) Use GetEndpoint() API which supports all transports.
 func (subInstance *EZMQSubscriber) GetPort() int {
 	return subInstance.port
 }
 
-func getSubSocketAddress(ip string, port int) string {
-	return string(SUB_TCP_PREFIX) + ip + ":" + strconv.Itoa(port)
+// Get endpoint of publisher to which subscribed.
+func (subInstance *EZMQSubscriber) GetEndpoint() *EZMQEndpoint {
+	return subInstance.endpoint
 }
 
 func getInProcUniqueAddress() string {
@@ -0,0 +1,166 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+	"go.uber.org/zap"
+
+	"strings"
+	"time"
+)
+
+// Callback to get subscription changes on publisher. Topic is without
+// trailing forward slash, empty topic means subscription for all events.
+type EZMQSubscriptionCB func(topic string, subscribed bool)
+
+type subscriptionChange struct {
+	topic      string
+	subscribed bool
+}
+
+// Read subscription messages pending on XPUB socket and update subscriptions.
+// Caller should hold the publisher mutex.
+func (pubInstance *EZMQPublisher) readSubscriptions() []subscriptionChange {
+	var changes []subscriptionChange
+	if nil == pubInstance.publisher {
+		return changes
+	}
+	for {
+		frame, err := pubInstance.publisher.RecvBytes(zmq.DONTWAIT)
+		if nil != err {
+			if zmq.AsErrno(err) != zmq.EAGAIN {
+				logger.Debug("Error while receiving subscription", zap.Error(err))
+			}
+			break
+		}
+		// first byte is 1 for subscribe and 0 for un-subscribe
+		if len(frame) == 0 || frame[0] > 1 {
+			continue
+		}
+		topic := string(frame[1:])
+		subscribed := frame[0] == 1
+		if subscribed {
+			pubInstance.subscriptions[topic] = true
+		} else {
+			delete(pubInstance.subscriptions, topic)
+		}
+		changes = append(changes, subscriptionChange{topic: strings.TrimSuffix(topic, "/"), subscribed: subscribed})
+	}
+	return changes
+}
+
+// Call subscription callback for changes. Caller should not hold the
+// publisher mutex, so that callback can publish.
+func (pubInstance *EZMQPublisher) notifySubscriptions(changes []subscriptionChange) {
+	if len(changes) == 0 {
+		return
+	}
+	pubInstance.mutex.Lock()
+	callback := pubInstance.subscriptionCallback
+	pubInstance.mutex.Unlock()
+	if nil == callback {
+		return
+	}
+	for _, change := range changes {
+		callback(change.topic, change.subscribed)
+	}
+}
+
+// Check whether any subscription matches the sanitized topic. Caller should
+// hold the publisher mutex.
+func (pubInstance *EZMQPublisher) hasSubscribers(topic string) bool {
+	for prefix := range pubInstance.subscriptions {
+		if strings.HasPrefix(topic, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Track subscriptions till tracker is stopped, so that subscription callback
+// is called without publishing. ZeroMQ sockets are not thread safe, so XPUB
+// socket is read under the publisher mutex instead of being polled here.
+// Stop is checked again under the mutex, as publisher may be stopped or
+// restarted while waiting for it.
+func (pubInstance *EZMQPublisher) trackSubscriptions(trackerStop chan struct{}) {
+	ticker := time.NewTicker(CONTEXT_POLL_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-trackerStop:
+			logger.Debug("Subscription tracker stopped")
+			return
+		case <-ticker.C:
+			pubInstance.mutex.Lock()
+			select {
+			case <-trackerStop:
+				pubInstance.mutex.Unlock()
+				logger.Debug("Subscription tracker stopped")
+				return
+			default:
+			}
+			changes := pubInstance.readSubscriptions()
+			pubInstance.mutex.Unlock()
+			pubInstance.notifySubscriptions(changes)
+		}
+	}
+}
+
+// Check whether any subscriber is subscribed for events on the given topic.
+// Empty topic checks for subscribers of events published without topic.
+//
+// Note:
+// (1) Subscription of a topic matches its sub-topics as well.
+//
+// (2) Returns false if publisher is not started or topic is invalid.
+func (pubInstance *EZMQPublisher) HasSubscribers(topic string) bool {
+	validTopic := sanitizeTopic(topic)
+	if topic != "" && validTopic == "" {
+		return false
+	}
+	result, started := pubInstance.checkSubscribers(validTopic)
+	return result && started
+}
+
+// Read pending subscriptions and check whether any subscription matches the
+// sanitized topic. Second value is false if publisher is not started.
+func (pubInstance *EZMQPublisher) checkSubscribers(topic string) (bool, bool) {
+	pubInstance.mutex.Lock()
+	changes := pubInstance.readSubscriptions()
+	started := nil != pubInstance.publisher
+	result := pubInstance.hasSubscribers(topic)
+	pubInstance.mutex.Unlock()
+	pubInstance.notifySubscriptions(changes)
+	return result, started
+}
+
+// Set callback to get subscription changes of the publisher.
+//
+// Note:
+// (1) Callback is called on subscribe of the first subscriber and un-subscribe
+// of the last subscriber of a topic. Subscriptions of disconnected
+// subscribers are un-subscribed.
+//
+// (2) This API should be called before Start() API, subscriptions are then
+// tracked by a go routine till Stop().
+func (pubInstance *EZMQPublisher) SetSubscriptionCallback(subscriptionCallback EZMQSubscriptionCB) {
+	pubInstance.mutex.Lock()
+	defer pubInstance.mutex.Unlock()
+	pubInstance.subscriptionCallback = subscriptionCallback
+}
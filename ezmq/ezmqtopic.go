@@ -0,0 +1,214 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Wildcard which matches exactly one topic level. For example:
+// home/+/temperature matches home/livingroom/temperature.
+const TOPIC_WILDCARD_SINGLE = "+"
+
+// Wildcard which matches any number of topic levels including the parent
+// level itself. It should be the last level. For example: home/# matches
+// home, home/livingroom and home/livingroom/temperature.
+const TOPIC_WILDCARD_MULTI = "#"
+
+const TOPIC_LEVEL_PATTERN = "^[a-zA-Z0-9-_.]+$"
+
+var topicLevelRegexp = regexp.MustCompile(TOPIC_LEVEL_PATTERN)
+
+type topicNode struct {
+	children map[string]*topicNode
+	count    int
+	handlers []topicHandler
+}
+
+func newTopicNode() *topicNode {
+	return &topicNode{children: make(map[string]*topicNode)}
+}
+
+// Filters received topics of subscriber. Plain topics are matched by prefix
+// as ZeroMQ does, wildcard topics are matched level by level using a trie.
+type topicFilter struct {
+	prefixes map[string]int
+	root     *topicNode
+}
+
+func newTopicFilter() *topicFilter {
+	filter := &topicFilter{}
+	filter.reset()
+	return filter
+}
+
+func (filter *topicFilter) reset() {
+	filter.prefixes = make(map[string]int)
+	filter.root = newTopicNode()
+}
+
+func isWildcardTopic(topic string) bool {
+	return strings.Contains(topic, TOPIC_WILDCARD_SINGLE) || strings.Contains(topic, TOPIC_WILDCARD_MULTI)
+}
+
+// Validate the wildcard topic. Returns topic without trailing forward slash,
+// or empty string if topic is not valid.
+func sanitizeTopicPattern(topic string) string {
+	topic = strings.TrimSuffix(topic, "/")
+	if topic == "" {
+		return ""
+	}
+	levels := strings.Split(topic, "/")
+	for i, level := range levels {
+		if level == TOPIC_WILDCARD_MULTI {
+			if i != len(levels)-1 {
+				return ""
+			}
+		} else if level != TOPIC_WILDCARD_SINGLE && false == topicLevelRegexp.MatchString(level) {
+			return ""
+		}
+	}
+	return topic
+}
+
+// Get the widest prefix of wildcard topic which can be subscribed on ZeroMQ
+// socket, so that publisher still filters the other topics.
+func getTopicPrefix(pattern string) string {
+	var prefix string
+	for _, level := range strings.Split(pattern, "/") {
+		if level == TOPIC_WILDCARD_SINGLE || level == TOPIC_WILDCARD_MULTI {
+			break
+		}
+		prefix = prefix + level + "/"
+	}
+	return prefix
+}
+
+// Get the prefix to be subscribed on ZeroMQ socket and the topic to be added
+// to filter. Topic will be empty string if it is not valid.
+func getSubscriptionTopic(topic string) (string, string) {
+	if isWildcardTopic(topic) {
+		pattern := sanitizeTopicPattern(topic)
+		return getTopicPrefix(pattern), pattern
+	}
+	validTopic := sanitizeTopic(topic)
+	return validTopic, validTopic
+}
+
+// Add topic to filter. Wildcard topic should be sanitized with
+// sanitizeTopicPattern and plain topic with sanitizeTopic.
+func (filter *topicFilter) add(topic string) {
+	if false == isWildcardTopic(topic) {
+		filter.prefixes[topic]++
+		return
+	}
+	getTopicNode(filter.root, topic).count++
+}
+
+// Get node of the wildcard topic, nodes are created if not present.
+func getTopicNode(root *topicNode, topic string) *topicNode {
+	node := root
+	for _, level := range strings.Split(topic, "/") {
+		child, exists := node.children[level]
+		if false == exists {
+			child = newTopicNode()
+			node.children[level] = child
+		}
+		node = child
+	}
+	return node
+}
+
+// Remove nodes of the wildcard topic which are not used anymore.
+func pruneTopicNode(node *topicNode, levels []string) {
+	if len(levels) == 0 {
+		return
+	}
+	child, exists := node.children[levels[0]]
+	if false == exists {
+		return
+	}
+	pruneTopicNode(child, levels[1:])
+	if child.count == 0 && len(child.handlers) == 0 && len(child.children) == 0 {
+		delete(node.children, levels[0])
+	}
+}
+
+// Remove topic added with add. Returns false if topic was not added.
+func (filter *topicFilter) remove(topic string) bool {
+	if false == isWildcardTopic(topic) {
+		count, exists := filter.prefixes[topic]
+		if false == exists {
+			return false
+		}
+		if count > 1 {
+			filter.prefixes[topic] = count - 1
+		} else {
+			delete(filter.prefixes, topic)
+		}
+		return true
+	}
+	node := getTopicNode(filter.root, topic)
+	removed := node.count > 0
+	if removed {
+		node.count--
+	}
+	pruneTopicNode(filter.root, strings.Split(topic, "/"))
+	return removed
+}
+
+// Check whether received topic [without trailing forward slash] matches any
+// topic of the filter.
+func (filter *topicFilter) match(topic string) bool {
+	for prefix := range filter.prefixes {
+		if strings.HasPrefix(topic+"/", prefix) {
+			return true
+		}
+	}
+	var matched bool = false
+	walkTopicNode(filter.root, strings.Split(topic, "/"), func(node *topicNode) {
+		matched = matched || node.count > 0
+	})
+	return matched
+}
+
+// Visit all the nodes whose wildcard topic matches the given topic levels.
+func walkTopicNode(node *topicNode, levels []string, visit func(*topicNode)) {
+	if child, exists := node.children[TOPIC_WILDCARD_MULTI]; exists {
+		visit(child)
+	}
+	if len(levels) == 0 {
+		visit(node)
+		return
+	}
+	if child, exists := node.children[levels[0]]; exists {
+		walkTopicNode(child, levels[1:], visit)
+	}
+	if child, exists := node.children[TOPIC_WILDCARD_SINGLE]; exists {
+		walkTopicNode(child, levels[1:], visit)
+	}
+}
+
+// Check whether topic [without trailing forward slash] matches the wildcard
+// topic sanitized with sanitizeTopicPattern.
+func matchTopicPattern(pattern string, topic string) bool {
+	filter := newTopicFilter()
+	filter.add(pattern)
+	return filter.match(topic)
+}
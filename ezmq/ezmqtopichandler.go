@@ -0,0 +1,184 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	"sort"
+	"strings"
+)
+
+// Identifier of a topic handler, returned by AddTopicHandler API to remove
+// the handler. Valid identifiers are non-zero.
+type EZMQTopicHandlerID uint64
+
+type topicHandler struct {
+	id      EZMQTopicHandlerID
+	handler EZMQSubTopicCB
+}
+
+// Routes received messages to the handlers registered per topic. Plain topics
+// are matched by prefix as subscriptions are, wildcard topics are matched
+// using trie.
+type topicRouter struct {
+	prefixes map[string][]topicHandler
+	root     *topicNode
+	topics   map[EZMQTopicHandlerID]string
+	lastID   EZMQTopicHandlerID
+}
+
+func newTopicRouter() *topicRouter {
+	router := &topicRouter{}
+	router.prefixes = make(map[string][]topicHandler)
+	router.root = newTopicNode()
+	router.topics = make(map[EZMQTopicHandlerID]string)
+	return router
+}
+
+func (router *topicRouter) add(topic string, handler EZMQSubTopicCB) EZMQTopicHandlerID {
+	router.lastID++
+	entry := topicHandler{id: router.lastID, handler: handler}
+	router.topics[entry.id] = topic
+	if false == isWildcardTopic(topic) {
+		router.prefixes[topic] = append(router.prefixes[topic], entry)
+		return entry.id
+	}
+	node := getTopicNode(router.root, topic)
+	node.handlers = append(node.handlers, entry)
+	return entry.id
+}
+
+// Remove the handler with given identifier. Returns false if there is no such
+// handler.
+func (router *topicRouter) remove(id EZMQTopicHandlerID) bool {
+	topic, exists := router.topics[id]
+	if false == exists {
+		return false
+	}
+	delete(router.topics, id)
+	if false == isWildcardTopic(topic) {
+		router.prefixes[topic] = removeTopicHandler(router.prefixes[topic], id)
+		if len(router.prefixes[topic]) == 0 {
+			delete(router.prefixes, topic)
+		}
+		return true
+	}
+	node := getTopicNode(router.root, topic)
+	node.handlers = removeTopicHandler(node.handlers, id)
+	pruneTopicNode(router.root, strings.Split(topic, "/"))
+	return true
+}
+
+func removeTopicHandler(handlers []topicHandler, id EZMQTopicHandlerID) []topicHandler {
+	var result []topicHandler
+	for _, entry := range handlers {
+		if entry.id != id {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// Call all the handlers matching received topic [without trailing forward
+// slash] in the order they are added. Returns false if there is no matching
+// handler.
+func (router *topicRouter) route(topic string, message EZMQMessage) bool {
+	var handlers []topicHandler
+	for prefix, prefixHandlers := range router.prefixes {
+		if strings.HasPrefix(topic+"/", prefix) {
+			handlers = append(handlers, prefixHandlers...)
+		}
+	}
+	walkTopicNode(router.root, strings.Split(topic, "/"), func(node *topicNode) {
+		handlers = append(handlers, node.handlers...)
+	})
+	// identifiers are increasing, so they give the order of addition
+	sort.Slice(handlers, func(i, j int) bool {
+		return handlers[i].id < handlers[j].id
+	})
+	for _, entry := range handlers {
+		entry.handler(topic, message)
+	}
+	return len(handlers) > 0
+}
+
+// Subscribe for event/messages on a particular topic and add handler for it.
+// Messages on the topic are delivered to handler instead of the subscriber
+// callbacks. Returns identifier of the handler.
+//
+// Note:
+// (1) Topic can have wildcards as SubscribeForTopic API.
+//
+// (2) To un-subscribe use UnSubscribeForTopic API, handler is removed only
+// with RemoveTopicHandler API.
+func (subInstance *EZMQSubscriber) SubscribeForTopicWithHandler(topic string,
+	handler EZMQSubTopicCB) (EZMQTopicHandlerID, EZMQErrorCode) {
+	if nil == handler {
+		return 0, subInstance.lastError.set(newError(EZMQ_ERROR, "subscribe with handler", nil))
+	}
+	err := subInstance.subscribeForTopic(topic)
+	if nil != err {
+		return 0, subInstance.lastError.set(err)
+	}
+	return subInstance.AddTopicHandler(topic, handler)
+}
+
+// Add handler for the given topic. Received messages are delivered to all the
+// handlers whose topic matches, and to the subscriber callbacks only if no
+// handler matches. Returns identifier of the handler, which is used to remove
+// it.
+//
+// Note:
+// (1) Topic can have wildcards as SubscribeForTopic API. Topic without
+// wildcard matches its sub-topics as well.
+//
+// (2) This API does not subscribe for the topic. Handlers can be added and
+// removed while subscriber is running. Same handler can be added more than
+// once, each addition has its own identifier.
+//
+// (3) Handlers matching a message are called in the order they are added.
+//
+// (4) Handlers are not called if message channel is set.
+//
+// (5) Handlers are called on receiver go routine with the subscriber lock
+// held, so subscriber APIs should not be called from handler. They would
+// block forever.
+func (subInstance *EZMQSubscriber) AddTopicHandler(topic string, handler EZMQSubTopicCB) (EZMQTopicHandlerID,
+	EZMQErrorCode) {
+	_, validTopic := getSubscriptionTopic(topic)
+	if validTopic == "" {
+		return 0, subInstance.lastError.set(newError(EZMQ_INVALID_TOPIC, "add topic handler", nil))
+	}
+	if nil == handler {
+		return 0, subInstance.lastError.set(newError(EZMQ_ERROR, "add topic handler", nil))
+	}
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	id := subInstance.topicRouter.add(validTopic, handler)
+	return id, subInstance.lastError.set(nil)
+}
+
+// Remove the handler with given identifier returned by AddTopicHandler API.
+// Other handlers of the same topic are not removed.
+func (subInstance *EZMQSubscriber) RemoveTopicHandler(id EZMQTopicHandlerID) EZMQErrorCode {
+	subInstance.mutex.Lock()
+	defer subInstance.mutex.Unlock()
+	if false == subInstance.topicRouter.remove(id) {
+		return subInstance.lastError.set(newError(EZMQ_ERROR, "remove topic handler", nil))
+	}
+	return subInstance.lastError.set(nil)
+}
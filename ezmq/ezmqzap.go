@@ -0,0 +1,342 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmq
+
+import (
+	zmq "github.com/pebbe/zmq4"
+	"go.uber.org/zap"
+
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Endpoint on which ZeroMQ sends authentication requests.
+const ZAP_ENDPOINT = "inproc://zeromq.zap.01"
+
+// ZAP protocol version.
+const ZAP_VERSION = "1.0"
+
+// Constants represents ZAP status codes.
+const (
+	ZAP_STATUS_SUCCESS       = "200"
+	ZAP_STATUS_FAILURE       = "400"
+	ZAP_STATUS_INTERNAL_FAIL = "500"
+)
+
+// Callback to get connections rejected by publisher authentication.
+type EZMQAuthFailureCB func(failure EZMQAuthFailure)
+
+// Structure represents a connection rejected by publisher authentication.
+//
+// Mechanism is one of NULL, PLAIN and CURVE. UserID is Z85 encoded public key
+// of client for CURVE and user name for PLAIN.
+type EZMQAuthFailure struct {
+	Mechanism string
+	Address   string
+	UserID    string
+	Reason    string
+}
+
+type zapRequest struct {
+	domain      string
+	address     string
+	mechanism   string
+	credentials [][]byte
+}
+
+// Authentication policy of a publisher. It can be changed while publisher is
+// running, changes apply to new connections.
+type zapPolicy struct {
+	mutex           sync.Mutex
+	disabled        bool
+	curveEnforced   bool
+	curveKeys       map[string]bool
+	addresses       *addressFilter
+	credentials     EZMQCredentialStore
+	failureCallback EZMQAuthFailureCB
+}
+
+func newZapPolicy() *zapPolicy {
+	policy := &zapPolicy{}
+	policy.curveKeys = make(map[string]bool)
+	policy.addresses = newAddressFilter()
+	return policy
+}
+
+// Authenticate the request. Returns user ID if request is allowed, otherwise
+// reason of failure.
+func (policy *zapPolicy) authenticate(request *zapRequest) (string, string, bool) {
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+	var userID string
+	if reason, allowed := policy.addresses.check(request.address); false == allowed {
+		return userID, reason, false
+	}
+	if request.mechanism == "PLAIN" {
+		if len(request.credentials) < 2 || nil == policy.credentials {
+			return userID, "invalid username or password", false
+		}
+		userID = string(request.credentials[0])
+		if false == policy.credentials.Authenticate(userID, request.credentials[1]) {
+			return userID, "invalid username or password", false
+		}
+	}
+	if request.mechanism == "CURVE" && len(request.credentials) > 0 {
+		userID = zmq.Z85encode(string(request.credentials[0]))
+		if true == policy.curveEnforced && false == policy.curveKeys[userID] {
+			return userID, "client key is not allowed", false
+		}
+	}
+	return userID, "", true
+}
+
+// Check if policy has any rule to authenticate. Policy lock should be held.
+func (policy *zapPolicy) hasRules() bool {
+	return policy.curveEnforced || len(policy.addresses.allowed) > 0 || len(policy.addresses.denied) > 0 ||
+		nil != policy.credentials
+}
+
+// Check if rules can be added to policy. Rules can not be added once
+// publisher is started without authentication, which is the case only if
+// ZAP_ENDPOINT is bound by another handler. Policy lock should be held.
+func (policy *zapPolicy) checkEnabled(op string) error {
+	if true == policy.disabled {
+		logger.Error("Authentication is not enabled on start of publisher")
+		return newError(EZMQ_ERROR, op, nil)
+	}
+	return nil
+}
+
+func (policy *zapPolicy) reportFailure(failure EZMQAuthFailure) {
+	policy.mutex.Lock()
+	failureCallback := policy.failureCallback
+	policy.mutex.Unlock()
+	if nil != failureCallback {
+		failureCallback(failure)
+	}
+}
+
+// Handles ZAP requests of a ZeroMQ context. Requests are dispatched to policy
+// of the ZAP domain, requests of unknown domains are allowed.
+type zapHandler struct {
+	mutex    sync.Mutex
+	policies map[string]*zapPolicy
+}
+
+var zapHandlers = make(map[*zmq.Context]*zapHandler)
+var zapHandlersMutex sync.Mutex
+var zapDomainCount uint64
+
+// Get ZAP handler of the context, handler is started if not running. Handler
+// runs till the context is terminated.
+func getZapHandler(context *zmq.Context) (*zapHandler, error) {
+	zapHandlersMutex.Lock()
+	defer zapHandlersMutex.Unlock()
+	handler, exists := zapHandlers[context]
+	if true == exists {
+		return handler, nil
+	}
+	socket, err := context.NewSocket(zmq.REP)
+	if nil != err {
+		return nil, newError(EZMQ_SOCKET_ERROR, "create ZAP socket", err)
+	}
+	socket.SetLinger(0)
+	err = socket.Bind(ZAP_ENDPOINT)
+	if nil != err {
+		socket.Close()
+		return nil, newError(EZMQ_SOCKET_ERROR, "bind ZAP socket", err)
+	}
+	handler = &zapHandler{policies: make(map[string]*zapPolicy)}
+	zapHandlers[context] = handler
+	go handler.run(context, socket)
+	return handler, nil
+}
+
+// Get a ZAP domain unique in the process.
+func getZapDomain() string {
+	return "ezmq-" + strconv.FormatUint(atomic.AddUint64(&zapDomainCount, 1), 10)
+}
+
+func (handler *zapHandler) register(domain string, policy *zapPolicy) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	handler.policies[domain] = policy
+}
+
+func (handler *zapHandler) unregister(domain string) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	delete(handler.policies, domain)
+}
+
+func (handler *zapHandler) run(context *zmq.Context, socket *zmq.Socket) {
+	defer func() {
+		zapHandlersMutex.Lock()
+		delete(zapHandlers, context)
+		zapHandlersMutex.Unlock()
+		socket.Close()
+	}()
+	for {
+		frames, err := socket.RecvMessageBytes(0)
+		if nil != err {
+			if zmq.EINTR == zmq.AsErrno(err) {
+				continue
+			}
+			// context is terminated
+			logger.Debug("ZAP handler stopped", zap.Error(err))
+			return
+		}
+		_, err = socket.SendMessage(handler.handle(frames))
+		if nil != err {
+			logger.Debug("Error while sending ZAP reply", zap.Error(err))
+		}
+	}
+}
+
+// Handle ZAP request and get reply frames.
+func (handler *zapHandler) handle(frames [][]byte) []string {
+	if len(frames) < 6 || string(frames[0]) != ZAP_VERSION {
+		logger.Error("Invalid ZAP request")
+		var requestID string
+		if len(frames) > 1 {
+			requestID = string(frames[1])
+		}
+		return []string{ZAP_VERSION, requestID, ZAP_STATUS_INTERNAL_FAIL, "Invalid request", "", ""}
+	}
+	requestID := string(frames[1])
+	request := &zapRequest{domain: string(frames[2]), address: string(frames[3]), mechanism: string(frames[5]),
+		credentials: frames[6:]}
+
+	handler.mutex.Lock()
+	policy, exists := handler.policies[request.domain]
+	handler.mutex.Unlock()
+	if false == exists {
+		return []string{ZAP_VERSION, requestID, ZAP_STATUS_SUCCESS, "OK", "", ""}
+	}
+	userID, reason, allowed := policy.authenticate(request)
+	if false == allowed {
+		logger.Debug("Connection rejected", zap.String("address", request.address), zap.String("reason", reason))
+		policy.reportFailure(EZMQAuthFailure{Mechanism: request.mechanism, Address: request.address, UserID: userID,
+			Reason: reason})
+		return []string{ZAP_VERSION, requestID, ZAP_STATUS_FAILURE, reason, "", ""}
+	}
+	return []string{ZAP_VERSION, requestID, ZAP_STATUS_SUCCESS, "OK", userID, ""}
+}
+
+// Start authentication of publisher socket. Authentication policy of
+// publisher is applied on its ZAP domain, so that rules can be added while
+// publisher is running. Policy without rules allows all the connections.
+//
+// Note:
+// (1) ZeroMQ allows only one ZAP handler per context, which binds
+// ZAP_ENDPOINT. If application runs its own ZAP handler [e.g. zmq.AuthStart]
+// on the context of EZMQAPI instance, publisher without rules is started
+// without authentication and publisher with rules fails to start.
+//
+// (2) Once handler is running, ZeroMQ sends requests of all the CURVE and
+// PLAIN sockets of the context to it. Requests of other sockets are allowed.
+func (pubInstance *EZMQPublisher) startAuthentication() error {
+	policy := pubInstance.authPolicy
+	handler, err := getZapHandler(pubInstance.context)
+	if nil == err {
+		err = pubInstance.publisher.SetZapDomain(pubInstance.zapDomain)
+		if nil != err {
+			return newError(EZMQ_SOCKET_ERROR, "set ZAP domain", err)
+		}
+		handler.register(pubInstance.zapDomain, policy)
+		pubInstance.zapHandler = handler
+		return nil
+	}
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+	if true == policy.hasRules() {
+		return err
+	}
+	logger.Info("Publisher is started without authentication", zap.Error(err))
+	policy.disabled = true
+	return nil
+}
+
+func (pubInstance *EZMQPublisher) stopAuthentication() {
+	if nil != pubInstance.zapHandler {
+		pubInstance.zapHandler.unregister(pubInstance.zapDomain)
+		pubInstance.zapHandler = nil
+	}
+	pubInstance.authPolicy.mutex.Lock()
+	pubInstance.authPolicy.disabled = false
+	pubInstance.authPolicy.mutex.Unlock()
+}
+
+// Set callback to get connections rejected by authentication of publisher.
+func (pubInstance *EZMQPublisher) SetAuthFailureCallback(failureCallback EZMQAuthFailureCB) {
+	pubInstance.authPolicy.mutex.Lock()
+	defer pubInstance.authPolicy.mutex.Unlock()
+	pubInstance.authPolicy.failureCallback = failureCallback
+}
+
+// Allow subscriber with the given client public key to connect.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+//
+// (2) Once a key is added, only subscribers with allowed keys can connect.
+// Rejected connections are reported to callback set by SetAuthFailureCallback
+// API.
+//
+// (3) Keys can be added and removed while publisher is running. Changes apply
+// to new connections, existing connections are not closed.
+//
+// (4) Authentication can not be used along with other ZAP handlers [e.g.
+// zmq.AuthStart] on the same EZMQAPI instance. If other handler is running,
+// publisher is started without authentication unless a client key, address
+// or credential store is set before Start() API, and this API returns
+// EZMQ_ERROR till publisher is stopped.
+func (pubInstance *EZMQPublisher) AddClientKey(clientPublicKey []byte) EZMQErrorCode {
+	if false == isValidKey(clientPublicKey) {
+		return pubInstance.lastError.set(newError(EZMQ_KEY_INVALID, "add client key", nil))
+	}
+	policy := pubInstance.authPolicy
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+	if err := policy.checkEnabled("add client key"); nil != err {
+		return pubInstance.lastError.set(err)
+	}
+	policy.curveEnforced = true
+	policy.curveKeys[string(clientPublicKey)] = true
+	return pubInstance.lastError.set(nil)
+}
+
+// Remove client public key added by AddClientKey API.
+//
+// Note:
+// (1) Subscribers are still restricted to allowed keys even if all the keys
+// are removed.
+func (pubInstance *EZMQPublisher) RemoveClientKey(clientPublicKey []byte) EZMQErrorCode {
+	if false == isValidKey(clientPublicKey) {
+		return pubInstance.lastError.set(newError(EZMQ_KEY_INVALID, "remove client key", nil))
+	}
+	policy := pubInstance.authPolicy
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+	if false == policy.curveKeys[string(clientPublicKey)] {
+		return pubInstance.lastError.set(newError(EZMQ_ERROR, "remove client key", nil))
+	}
+	delete(policy.curveKeys, string(clientPublicKey))
+	return pubInstance.lastError.set(nil)
+}
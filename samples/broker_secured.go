@@ -0,0 +1,136 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package main
+
+import (
+	ezmq "go/ezmq"
+
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// put publisher server public key
+const ServerPublicKey = ""
+
+// put broker frontend client keys
+const ClientPublicKey = ""
+const ClientSecretKey = ""
+
+// put broker backend server key
+const BackendSecretKey = ""
+
+func printError() {
+	fmt.Printf("\nRe-run the application as shown in below example: \n")
+	fmt.Printf("\n  (1) For broker with frontend port 5562 and backend port 5563: ")
+	fmt.Printf("\n      ./broker_secured -fport 5562 -bport 5563\n")
+	fmt.Printf("\n  (2) For broker with frontend port 5562 and backend port 5563: [Secured] ")
+	fmt.Printf("\n      ./broker_secured -fport 5562 -bport 5563 -secured 1\n")
+	os.Exit(-1)
+}
+
+func main() {
+	var frontendPort int
+	var backendPort int
+	var isSecured int = 0
+	var result ezmq.EZMQErrorCode
+	var broker *ezmq.EZMQBroker = nil
+	var instance *ezmq.EZMQAPI = nil
+
+	// get ports from command line arguments
+	if len(os.Args) != 5 && len(os.Args) != 7 {
+		printError()
+	}
+
+	for n := 1; n < len(os.Args); n++ {
+		if 0 == strings.Compare(os.Args[n], "-fport") {
+			frontendPort, _ = strconv.Atoi(os.Args[n+1])
+			fmt.Printf("\nGiven frontend Port %d: ", frontendPort)
+			n = n + 1
+		} else if 0 == strings.Compare(os.Args[n], "-bport") {
+			backendPort, _ = strconv.Atoi(os.Args[n+1])
+			fmt.Printf("\nGiven backend Port %d: ", backendPort)
+			n = n + 1
+		} else if 0 == strings.Compare(os.Args[n], "-secured") {
+			isSecured, _ = strconv.Atoi(os.Args[n+1])
+			fmt.Printf("\nSecured %d: ", isSecured)
+			n = n + 1
+		} else {
+			printError()
+		}
+	}
+
+	//Handler for ctrl+c
+	osSignal := make(chan os.Signal, 1)
+	signal.Notify(osSignal, syscall.SIGINT, syscall.SIGTERM)
+
+	//get singleton instance
+	instance = ezmq.GetInstance()
+
+	//Initilize the EZMQ SDK
+	result = instance.Initialize()
+	fmt.Printf("\n[Initialize] Error code is: %d", result)
+	if result != ezmq.EZMQ_OK {
+		fmt.Printf("Error while initializing\n")
+		os.Exit(-1)
+	}
+	broker = ezmq.GetEZMQBroker(ezmq.GetEZMQTCPEndpoint("*", frontendPort), ezmq.GetEZMQTCPEndpoint("*", backendPort))
+	if nil == broker {
+		fmt.Printf("\nError while creating broker\n")
+		os.Exit(-1)
+	}
+
+	//set keys
+	if 1 == isSecured {
+		result = broker.SetFrontendKeys([]byte(ServerPublicKey), []byte(ClientSecretKey), []byte(ClientPublicKey))
+		if result != ezmq.EZMQ_OK {
+			fmt.Printf("\nError while setting frontend keys\n")
+			os.Exit(-1)
+		}
+		result = broker.SetBackendPrivateKey([]byte(BackendSecretKey))
+		if result != ezmq.EZMQ_OK {
+			fmt.Printf("\nError while setting backend key\n")
+			os.Exit(-1)
+		}
+	}
+
+	//start broker
+	result = broker.Start()
+	if result != ezmq.EZMQ_OK {
+		fmt.Printf("\nError while starting broker\n")
+		os.Exit(-1)
+	}
+	fmt.Printf("\n[Start] Error code is: %d", result)
+	fmt.Printf("\nPublishers should connect to: %s", broker.GetFrontendEndpoint())
+	fmt.Printf("\nSubscribers should connect to: %s\n", broker.GetBackendEndpoint())
+
+	// run until ctrl+c
+	sig := <-osSignal
+	fmt.Println(sig)
+
+	//stop broker
+	result = broker.Stop()
+	if result != ezmq.EZMQ_OK {
+		fmt.Printf("Error while Stopping broker")
+	}
+	fmt.Printf("\n[Stop] Error code is: %d\n", result)
+	instance.Terminate()
+}
@@ -0,0 +1,83 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package main
+
+import (
+	ezmq "go/ezmq"
+
+	"fmt"
+	"os"
+	"strings"
+)
+
+func printError() {
+	fmt.Printf("\nRe-run the application as shown in below example: \n")
+	fmt.Printf("\n  (1) For generating certificate to server and server_secret files: ")
+	fmt.Printf("\n      ./keygen_secured -out server\n")
+	fmt.Printf("\n  (2) For generating certificate with name metadata: ")
+	fmt.Printf("\n      ./keygen_secured -out server -name publisher1\n")
+	os.Exit(-1)
+}
+
+func main() {
+	var filename string
+	var name string
+	var result ezmq.EZMQErrorCode
+	var cert *ezmq.EZMQCertificate = nil
+
+	// get file name from command line arguments
+	if len(os.Args) != 3 && len(os.Args) != 5 {
+		printError()
+	}
+
+	for n := 1; n < len(os.Args); n++ {
+		if 0 == strings.Compare(os.Args[n], "-out") {
+			filename = os.Args[n+1]
+			n = n + 1
+		} else if 0 == strings.Compare(os.Args[n], "-name") {
+			name = os.Args[n+1]
+			n = n + 1
+		} else {
+			printError()
+		}
+	}
+	if filename == "" {
+		printError()
+	}
+
+	cert, result = ezmq.GetEZMQCertificate()
+	if result != ezmq.EZMQ_OK {
+		fmt.Printf("\nError while generating key pair: %d\n", result)
+		os.Exit(-1)
+	}
+	if name != "" && cert.SetMeta("name", name) != ezmq.EZMQ_OK {
+		fmt.Printf("\nInvalid name: %s\n", name)
+		os.Exit(-1)
+	}
+	result = cert.Save(filename)
+	if result != ezmq.EZMQ_OK {
+		fmt.Printf("\nError while saving certificate: %d\n", result)
+		os.Exit(-1)
+	}
+	fmt.Printf("\nPublic certificate: %s", filename)
+	fmt.Printf("\nSecret certificate: %s%s", filename, ezmq.SECRET_CERT_SUFFIX)
+	fmt.Printf("\nPublic key: %s\n", cert.GetPublicKey())
+
+	// wipe secret key
+	cert.Close()
+}
@@ -49,6 +49,18 @@ func printByteData(byteData []byte) {
 	fmt.Printf("\n--------------------------------------\n")
 }
 
+func printAMLData(amlData []byte) {
+	fmt.Printf("\n--------------------------------------\n")
+	fmt.Printf("%s", string(amlData))
+	fmt.Printf("\n--------------------------------------\n")
+}
+
+func printJSONData(jsonData []byte) {
+	fmt.Printf("\n--------------------------------------\n")
+	fmt.Printf("%s", string(jsonData))
+	fmt.Printf("\n--------------------------------------\n")
+}
+
 func printError() {
 	fmt.Printf("\nRe-run the application as shown in below examples: \n")
 	fmt.Printf("\n  (1) For subscribing without topic: ")
@@ -118,6 +130,14 @@ func main() {
 			fmt.Printf("\nContent-type is Byte data\n")
 			byteData := ezmqMsg.(ezmq.EZMQByteData)
 			printByteData(byteData.GetByteData())
+		} else if contentType == ezmq.EZMQ_CONTENT_TYPE_AML {
+			fmt.Printf("\nContent-type is AML data\n")
+			amlData := ezmqMsg.(ezmq.EZMQAMLData)
+			printAMLData(amlData.GetAMLData())
+		} else if contentType == ezmq.EZMQ_CONTENT_TYPE_JSON {
+			fmt.Printf("\nContent-type is JSON data\n")
+			jsonData := ezmqMsg.(ezmq.EZMQJSONData)
+			printJSONData(jsonData.GetJSONData())
 		}
 	}
 	subTopicCB := func(topic string, ezmqMsg ezmq.EZMQMessage) {
@@ -131,6 +151,14 @@ func main() {
 			fmt.Printf("\nContent-type is Byte data\n")
 			byteData := ezmqMsg.(ezmq.EZMQByteData)
 			printByteData(byteData.GetByteData())
+		} else if contentType == ezmq.EZMQ_CONTENT_TYPE_AML {
+			fmt.Printf("\nContent-type is AML data\n")
+			amlData := ezmqMsg.(ezmq.EZMQAMLData)
+			printAMLData(amlData.GetAMLData())
+		} else if contentType == ezmq.EZMQ_CONTENT_TYPE_JSON {
+			fmt.Printf("\nContent-type is JSON data\n")
+			jsonData := ezmqMsg.(ezmq.EZMQJSONData)
+			printJSONData(jsonData.GetJSONData())
 		}
 	}
 
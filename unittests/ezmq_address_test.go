@@ -0,0 +1,128 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"testing"
+	"time"
+)
+
+func TestAddressFilter(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	failures := make(chan ezmq.EZMQAuthFailure, 10)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.SetAuthFailureCallback(func(failure ezmq.EZMQAuthFailure) { failures <- failure })
+	if ezmq.EZMQ_ERROR != publisher.DenyAddress("invalid") {
+		t.Errorf("\nInvalid address accepted\n")
+	}
+	if publisher.DenyAddress("127.0.0.0/8") != 0 {
+		t.Fatalf("\nError while denying address: %v\n", publisher.GetLastError())
+	}
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+
+	denied := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	if denied.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", denied.GetLastError())
+	}
+	select {
+	case failure := <-failures:
+		if failure.Mechanism != "NULL" || failure.Address != "127.0.0.1" {
+			t.Errorf("\nWrong failure: %+v\n", failure)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("\nTimeout while waiting for authentication failure\n")
+	}
+	denied.Stop()
+
+	// lists are changed while publisher is running
+	if publisher.RemoveAddress("127.0.0.0/8") != 0 || publisher.AllowAddress("127.0.0.1") != 0 {
+		t.Fatalf("\nError while changing addresses: %v\n", publisher.GetLastError())
+	}
+	if ezmq.EZMQ_ERROR != publisher.RemoveAddress("127.0.0.0/8") {
+		t.Errorf("\nRemoved address twice\n")
+	}
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+	receiveReverse(t, publisher, messages)
+}
+
+func TestAddressAddedAfterStart(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	failures := make(chan ezmq.EZMQAuthFailure, 10)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.SetAuthFailureCallback(func(failure ezmq.EZMQAuthFailure) { failures <- failure })
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+	// publisher is started without any rule
+	if publisher.DenyAddress("127.0.0.0/8") != 0 {
+		t.Fatalf("\nError while denying address: %v\n", publisher.GetLastError())
+	}
+
+	denied := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	if denied.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", denied.GetLastError())
+	}
+	defer denied.Stop()
+	select {
+	case failure := <-failures:
+		if failure.Address != "127.0.0.1" {
+			t.Errorf("\nWrong failure: %+v\n", failure)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("\nTimeout while waiting for authentication failure\n")
+	}
+}
+
+func TestAddressFilterSkipsIPC(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	endpoint, _ := ezmq.GetEZMQEndpoint("ipc:///tmp/ezmq-address-test.ipc")
+	publisher := ezmq.GetEZMQPublisherWithEndpoint(endpoint, startCB, stopCB, errorCB)
+	if publisher.AllowAddress("192.168.1.0/24") != 0 || publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriberWithEndpoint(endpoint, nil, nil)
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+	receiveReverse(t, publisher, messages)
+}
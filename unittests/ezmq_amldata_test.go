@@ -0,0 +1,133 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	ezmq "go/ezmq"
+	test_utils "go/unittests/utils"
+
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestGetAMLData(t *testing.T) {
+	amlData := test_utils.GetAMLDataEvent()
+	var array []byte = []byte(`<CAEXFile FileName="gateway.aml"><InstanceHierarchy Name="Plant"/></CAEXFile>`)
+	if false == bytes.Equal(array, amlData.GetAMLData()) {
+		t.Errorf("\nAssertion failed")
+	}
+}
+
+func TestSetAMLData(t *testing.T) {
+	var amlData ezmq.EZMQAMLData
+	var array []byte = []byte(`<?xml version="1.0"?><CAEXFile FileName="plant.aml"></CAEXFile>`)
+	if 0 != amlData.SetAMLData(array) {
+		t.Errorf("\nError while setting AML data")
+	}
+	if false == bytes.Equal(array, amlData.GetAMLData()) {
+		t.Errorf("\nAssertion failed")
+	}
+}
+
+func TestSetInvalidAMLData(t *testing.T) {
+	var amlData ezmq.EZMQAMLData
+	if 0 == amlData.SetAMLData(nil) {
+		t.Errorf("\nAssertion failed")
+	}
+	if 0 == amlData.SetAMLData([]byte(`<CAEXFile><InstanceHierarchy>`)) {
+		t.Errorf("\nAssertion failed")
+	}
+	if 0 == amlData.SetAMLData([]byte(`not xml`)) {
+		t.Errorf("\nAssertion failed")
+	}
+}
+
+func TestAMLDataContentType(t *testing.T) {
+	amlData := test_utils.GetAMLDataEvent()
+	if 2 != amlData.GetContentType() {
+		t.Errorf("\nAssertion failed")
+	}
+}
+
+func TestPublishEmptyAMLData(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	amlPublisher := ezmq.GetEZMQPublisher(test_utils.Port, startCB, stopCB, errorCB)
+	if nil == amlPublisher || amlPublisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer amlPublisher.Stop()
+
+	if amlPublisher.Publish(ezmq.EZMQAMLData{}) != ezmq.EZMQ_SERIALIZATION_FAILED ||
+		nil == errors.Unwrap(amlPublisher.GetLastError()) {
+		t.Errorf("\nEmpty AML data published: %v\n", amlPublisher.GetLastError())
+	}
+	if amlPublisher.Publish(&ezmq.EZMQAMLData{AMLData: []byte{}}) != ezmq.EZMQ_SERIALIZATION_FAILED {
+		t.Errorf("\nEmpty AML data pointer published\n")
+	}
+}
+
+func TestAMLRoundTrip(t *testing.T) {
+	received := make(chan ezmq.EZMQMessage, 10)
+	amlSubCB := func(ezmqMsg ezmq.EZMQMessage) { received <- ezmqMsg }
+	amlSubTopicCB := func(topic string, ezmqMsg ezmq.EZMQMessage) { received <- ezmqMsg }
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	amlPublisher := ezmq.GetEZMQPublisher(test_utils.Port, startCB, stopCB, errorCB)
+	if nil == amlPublisher || amlPublisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer amlPublisher.Stop()
+
+	amlSubscriber := ezmq.GetEZMQSubscriber(test_utils.Ip, test_utils.Port, amlSubCB, amlSubTopicCB)
+	if nil == amlSubscriber || amlSubscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer amlSubscriber.Stop()
+	if amlSubscriber.SubscribeForTopic(test_utils.Topic) != 0 {
+		t.Fatalf("\nError while subscribing\n")
+	}
+
+	amlData := test_utils.GetAMLDataEvent()
+	test_utils.PublishUntilReceived(t, func() {
+		if amlPublisher.PublishOnTopic(test_utils.Topic, amlData) != 0 {
+			t.Fatalf("\nError while publishing AML data\n")
+		}
+	}, func() bool {
+		select {
+		case ezmqMsg := <-received:
+			if ezmqMsg.GetContentType() != ezmq.EZMQ_CONTENT_TYPE_AML {
+				t.Fatalf("\nWrong content type\n")
+			}
+			receivedData, ok := ezmqMsg.(ezmq.EZMQAMLData)
+			if false == ok {
+				t.Fatalf("\nReceived message is not AML data\n")
+			}
+			if false == bytes.Equal(amlData.GetAMLData(), receivedData.GetAMLData()) {
+				t.Errorf("\nAML data mismatch\n")
+			}
+			return true
+		default:
+			return false
+		}
+	})
+}
@@ -21,6 +21,7 @@ import (
 	ezmq "go/ezmq"
 	test_utils "go/unittests/utils"
 
+	"sync"
 	"testing"
 )
 
@@ -71,3 +72,74 @@ func TestGetStatus(t *testing.T) {
 		t.Errorf("Wrong status code")
 	}
 }
+
+func TestGetInstanceConcurrent(t *testing.T) {
+	var wait sync.WaitGroup
+	instances := make([]*ezmq.EZMQAPI, 10)
+	for i := range instances {
+		wait.Add(1)
+		go func(index int) {
+			defer wait.Done()
+			instances[index] = ezmq.GetInstance()
+		}(i)
+	}
+	wait.Wait()
+	for _, instance := range instances {
+		if instance != ezmq.GetInstance() {
+			t.Errorf("Different instance returned")
+		}
+	}
+}
+
+func TestIndependentInstances(t *testing.T) {
+	instance1 := ezmq.GetEZMQAPI()
+	instance2 := ezmq.GetEZMQAPI()
+	if instance1 == instance2 || instance1 == ezmq.GetInstance() {
+		t.Fatalf("Instances are not independent")
+	}
+	if 0 != instance2.SetIoThreads(2) {
+		t.Errorf("Error while setting io threads")
+	}
+	if 0 != instance1.Initialize() || 0 != instance2.Initialize() {
+		t.Fatalf("Error while initializing")
+	}
+	defer instance2.Terminate()
+	if instance1.GetContext() == instance2.GetContext() {
+		t.Errorf("Instances share context")
+	}
+
+	publisher1 := ezmq.GetEZMQPublisher(test_utils.Port, startCB, stopCB, errorCB, ezmq.WithAPI(instance1))
+	publisher2 := ezmq.GetEZMQPublisher(test_utils.Port+1, startCB, stopCB, errorCB, ezmq.WithAPI(instance2))
+	if 0 != publisher1.Start() || 0 != publisher2.Start() {
+		t.Fatalf("Error while starting publishers")
+	}
+	defer publisher2.Stop()
+	publisher1.Stop()
+
+	// terminating one instance should not affect the other
+	if 0 != instance1.Terminate() {
+		t.Errorf("Error while terminating")
+	}
+	if 2 != instance2.GetStatus() {
+		t.Errorf("Wrong status code")
+	}
+	if 0 != publisher2.Publish(test_utils.GetEvent()) {
+		t.Errorf("Error while publishing on other instance")
+	}
+	if nil != ezmq.GetEZMQPublisher(test_utils.Port, startCB, stopCB, errorCB, ezmq.WithAPI(instance1)) {
+		t.Errorf("Publisher created on terminated instance")
+	}
+}
+
+func TestInstanceHeaderVersion(t *testing.T) {
+	instance := ezmq.GetEZMQAPI()
+	if ezmq.EZMQ_INVALID_VERSION != instance.SetHeaderVersion(3) {
+		t.Errorf("Set invalid header version")
+	}
+	if 0 != instance.SetHeaderVersion(ezmq.EZMQ_HEADER_VERSION_1) {
+		t.Errorf("Error while setting header version")
+	}
+	if ezmq.EZMQ_ERROR != instance.SetIoThreads(-1) {
+		t.Errorf("Set invalid io threads")
+	}
+}
@@ -0,0 +1,132 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	zmq "github.com/pebbe/zmq4"
+
+	"testing"
+)
+
+func getBroker() *ezmq.EZMQBroker {
+	return ezmq.GetEZMQBroker(ezmq.GetEZMQTCPEndpoint("*", utils.Port), ezmq.GetEZMQTCPEndpoint("*", utils.Port+1))
+}
+
+func TestBroker(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	broker := getBroker()
+	if nil == broker {
+		t.Fatalf("\nBroker is nil\n")
+	}
+	if broker.Start() != 0 {
+		t.Fatalf("\nError while starting broker: %v\n", broker.GetLastError())
+	}
+
+	subscriber := ezmq.GetEZMQSubscriberWithEndpoint(ezmq.GetEZMQTCPEndpoint(utils.Ip, utils.Port+1), nil, nil)
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+
+	publisher := ezmq.GetEZMQPublisherWithEndpoint(ezmq.GetEZMQTCPEndpoint(utils.Ip, utils.Port), startCB,
+		stopCB, errorCB, ezmq.WithReverseTopology())
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+	receiveReverse(t, publisher, messages)
+
+	if broker.Stop() != 0 {
+		t.Errorf("\nError while stopping broker: %v\n", broker.GetLastError())
+	}
+	if ezmq.EZMQ_NOT_STARTED != broker.Stop() {
+		t.Errorf("\nStopped broker twice\n")
+	}
+}
+
+func TestBrokerSecured(t *testing.T) {
+	publisherPublicKey, publisherSecretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	brokerPublicKey, brokerSecretKey, _ := zmq.NewCurveKeypair()
+	clientPublicKey, clientSecretKey, _ := zmq.NewCurveKeypair()
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	broker := getBroker()
+	if ezmq.EZMQ_KEY_INVALID != broker.SetBackendPrivateKey([]byte("invalid")) {
+		t.Errorf("\nInvalid key accepted\n")
+	}
+	broker.SetFrontendKeys([]byte(publisherPublicKey), []byte(clientSecretKey), []byte(clientPublicKey))
+	broker.SetBackendPrivateKey([]byte(brokerSecretKey))
+	if broker.Start() != 0 {
+		t.Fatalf("\nError while starting broker: %v\n", broker.GetLastError())
+	}
+	defer broker.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriberWithEndpoint(ezmq.GetEZMQTCPEndpoint(utils.Ip, utils.Port+1), nil, nil)
+	subscriber.SetClientKeys([]byte(clientSecretKey), []byte(clientPublicKey))
+	subscriber.SetServerPublicKey([]byte(brokerPublicKey))
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+
+	publisher := ezmq.GetEZMQPublisherWithEndpoint(ezmq.GetEZMQTCPEndpoint(utils.Ip, utils.Port), startCB,
+		stopCB, errorCB, ezmq.WithReverseTopology())
+	publisher.SetServerPrivateKey([]byte(publisherSecretKey))
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+	receiveReverse(t, publisher, messages)
+}
+
+func TestBrokerNegative(t *testing.T) {
+	if nil != ezmq.GetEZMQBroker(nil, ezmq.GetEZMQTCPEndpoint("*", utils.Port+1)) {
+		t.Errorf("\nBroker created without frontend\n")
+	}
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	broker1 := getBroker()
+	broker2 := getBroker()
+	if broker1.Start() != 0 {
+		t.Fatalf("\nError while starting broker: %v\n", broker1.GetLastError())
+	}
+	defer broker1.Stop()
+	if ezmq.EZMQ_BIND_IN_USE != broker2.Start() {
+		t.Errorf("\nStarted two brokers on same port\n")
+	}
+	if ezmq.EZMQ_NOT_STARTED != broker2.Stop() {
+		t.Errorf("\nStopped broker which is not started\n")
+	}
+}
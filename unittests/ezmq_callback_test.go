@@ -0,0 +1,162 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	zmq "github.com/pebbe/zmq4"
+
+	"strings"
+	"testing"
+	"time"
+)
+
+func waitCode(t *testing.T, codes chan ezmq.EZMQErrorCode, expected ezmq.EZMQErrorCode) {
+	select {
+	case code := <-codes:
+		if code != expected {
+			t.Errorf("\nWrong code: %s, expected: %s\n", code, expected)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("\nTimeout while waiting for callback\n")
+	}
+}
+
+func TestPublisherStatusCallbacks(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	starts := make(chan ezmq.EZMQErrorCode, 10)
+	stops := make(chan ezmq.EZMQErrorCode, 10)
+	publisher := ezmq.GetEZMQPublisher(utils.Port,
+		func(code ezmq.EZMQErrorCode) { starts <- code },
+		func(code ezmq.EZMQErrorCode) { stops <- code }, errorCB)
+	publisher.Start()
+	waitCode(t, starts, ezmq.EZMQ_OK)
+	// start of running publisher does not call start callback again
+	publisher.Start()
+	if 0 != len(starts) {
+		t.Errorf("\nStart callback called for running publisher\n")
+	}
+	publisher.Stop()
+	waitCode(t, stops, ezmq.EZMQ_OK)
+	publisher.Stop()
+	waitCode(t, stops, ezmq.EZMQ_NOT_STARTED)
+}
+
+func TestSubscriberStatusCallbacks(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	starts := make(chan ezmq.EZMQErrorCode, 10)
+	stops := make(chan ezmq.EZMQErrorCode, 10)
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	subscriber.SetStatusCallbacks(func(code ezmq.EZMQErrorCode) { starts <- code },
+		func(code ezmq.EZMQErrorCode) { stops <- code }, nil)
+	subscriber.Start()
+	waitCode(t, starts, ezmq.EZMQ_OK)
+	// start of running subscriber does not call start callback again
+	subscriber.Start()
+	if 0 != len(starts) {
+		t.Errorf("\nStart callback called for running subscriber\n")
+	}
+	subscriber.Stop()
+	waitCode(t, stops, ezmq.EZMQ_OK)
+}
+
+func TestErrorCallbackHandshakeFailed(t *testing.T) {
+	if major, minor, _ := zmq.Version(); major < 4 || (major == 4 && minor < 3) {
+		t.Skip("Handshake events are not supported")
+	}
+	_, serverSecretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	otherPublicKey, _, _ := zmq.NewCurveKeypair()
+	clientPublicKey, clientSecretKey, _ := zmq.NewCurveKeypair()
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	errors := make(chan ezmq.EZMQErrorCode, 10)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB,
+		func(code ezmq.EZMQErrorCode) { errors <- code })
+	publisher.SetServerPrivateKey([]byte(serverSecretKey))
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher.Stop()
+
+	// subscriber uses public key of other server, which is not a key problem
+	// of publisher
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	subscriber.SetClientKeys([]byte(clientSecretKey), []byte(clientPublicKey))
+	subscriber.SetServerPublicKey([]byte(otherPublicKey))
+	if subscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer subscriber.Stop()
+	waitCode(t, errors, ezmq.EZMQ_SOCKET_ERROR)
+}
+
+func TestErrorCallbackAuthFailed(t *testing.T) {
+	if major, minor, _ := zmq.Version(); major < 4 || (major == 4 && minor < 3) {
+		t.Skip("Handshake events are not supported")
+	}
+	serverPublicKey, serverSecretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	otherPublicKey, _, _ := zmq.NewCurveKeypair()
+	clientPublicKey, clientSecretKey, _ := zmq.NewCurveKeypair()
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	errors := make(chan ezmq.EZMQErrorCode, 10)
+	events := make(chan ezmq.EZMQEvent, 100)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB,
+		func(code ezmq.EZMQErrorCode) { errors <- code })
+	publisher.SetEventCallback(func(event ezmq.EZMQEvent) { events <- event })
+	publisher.SetServerPrivateKey([]byte(serverSecretKey))
+	publisher.AddClientKey([]byte(otherPublicKey))
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher.Stop()
+
+	// client key of subscriber is not allowed
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	subscriber.SetClientKeys([]byte(clientSecretKey), []byte(clientPublicKey))
+	subscriber.SetServerPublicKey([]byte(serverPublicKey))
+	if subscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer subscriber.Stop()
+	waitCode(t, errors, ezmq.EZMQ_KEY_INVALID)
+	event := waitEvent(t, events, ezmq.EZMQ_EVENT_HANDSHAKE_FAILED)
+	if false == strings.HasPrefix(event.Peer, "tcp://") {
+		t.Errorf("\nPeer of failed handshake is not reported: %s\n", event.Peer)
+	}
+}
@@ -0,0 +1,147 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateKeyPair(t *testing.T) {
+	publicKey, secretKey, result := ezmq.GenerateKeyPair()
+	if result != ezmq.EZMQ_OK {
+		t.Skip("CURVE is not supported")
+	}
+	if len(publicKey) != 40 || len(secretKey) != 40 {
+		t.Fatalf("\nWrong key length\n")
+	}
+	derived, result := ezmq.GetPublicKey(secretKey)
+	if result != ezmq.EZMQ_OK || false == bytes.Equal(derived, publicKey) {
+		t.Errorf("\nWrong public key derived\n")
+	}
+	if _, result = ezmq.GetPublicKey([]byte("invalid")); result != ezmq.EZMQ_KEY_INVALID {
+		t.Errorf("\nInvalid key accepted\n")
+	}
+}
+
+func TestCertificateSaveLoad(t *testing.T) {
+	cert, result := ezmq.GetEZMQCertificate()
+	if result != ezmq.EZMQ_OK {
+		t.Skip("CURVE is not supported")
+	}
+	dir, err := ioutil.TempDir("", "ezmqcert")
+	if nil != err {
+		t.Fatalf("\nError while creating directory: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "server")
+	cert.SetMeta("name", "publisher")
+	if cert.Save(filename) != ezmq.EZMQ_OK {
+		t.Fatalf("\nError while saving certificate\n")
+	}
+	loaded, result := ezmq.LoadEZMQCertificate(filename)
+	if result != ezmq.EZMQ_OK {
+		t.Fatalf("\nError while loading certificate: %d\n", result)
+	}
+	if false == bytes.Equal(loaded.GetPublicKey(), cert.GetPublicKey()) ||
+		false == bytes.Equal(loaded.GetSecretKey(), cert.GetSecretKey()) || loaded.GetMeta("name") != "publisher" {
+		t.Errorf("\nWrong certificate loaded\n")
+	}
+
+	// only public certificate is loaded once secret certificate is removed
+	os.Remove(filename + ezmq.SECRET_CERT_SUFFIX)
+	loaded, result = ezmq.LoadEZMQCertificate(filename)
+	if result != ezmq.EZMQ_OK || nil != loaded.GetSecretKey() {
+		t.Errorf("\nWrong public certificate loaded\n")
+	}
+}
+
+func TestCertificateMetaAndPermissions(t *testing.T) {
+	cert, result := ezmq.GetEZMQCertificate()
+	if result != ezmq.EZMQ_OK {
+		t.Skip("CURVE is not supported")
+	}
+	defer cert.Close()
+	dir, err := ioutil.TempDir("", "ezmqcert")
+	if nil != err {
+		t.Fatalf("\nError while creating directory: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if cert.SetMeta("name", "line\nbreak") != ezmq.EZMQ_ERROR || cert.SetMeta("name", `"both'`) != ezmq.EZMQ_ERROR ||
+		cert.SetMeta("a = b", "value") != ezmq.EZMQ_ERROR {
+		t.Errorf("\nInvalid metadata accepted\n")
+	}
+	if cert.SetMeta("name", `a = "b"`) != ezmq.EZMQ_OK || cert.SetMeta("id", "1") != ezmq.EZMQ_OK {
+		t.Fatalf("\nError while setting metadata\n")
+	}
+
+	// secret certificate replaces file which is readable by others
+	filename := filepath.Join(dir, "server")
+	ioutil.WriteFile(filename+ezmq.SECRET_CERT_SUFFIX, []byte(""), 0644)
+	if cert.Save(filename) != ezmq.EZMQ_OK {
+		t.Fatalf("\nError while saving certificate\n")
+	}
+	info, err := os.Stat(filename + ezmq.SECRET_CERT_SUFFIX)
+	if nil != err || info.Mode().Perm() != 0600 {
+		t.Errorf("\nWrong permissions of secret certificate\n")
+	}
+	info, err = os.Stat(filename)
+	if nil != err || info.Mode().Perm() != 0644 {
+		t.Errorf("\nWrong permissions of public certificate\n")
+	}
+	// temporary files are renamed over certificates
+	if files, _ := ioutil.ReadDir(dir); len(files) != 2 {
+		t.Errorf("\nUnexpected files in directory: %d\n", len(files))
+	}
+	// metadata is saved in order of names
+	data, _ := ioutil.ReadFile(filename)
+	if bytes.Index(data, []byte("id = ")) > bytes.Index(data, []byte("name = ")) {
+		t.Errorf("\nMetadata is not sorted\n")
+	}
+	loaded, result := ezmq.LoadEZMQCertificate(filename)
+	if result != ezmq.EZMQ_OK || loaded.GetMeta("name") != `a = "b"` || loaded.GetMeta("id") != "1" {
+		t.Errorf("\nWrong metadata loaded\n")
+	}
+}
+
+func TestCertificateNegative(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ezmqcert")
+	if nil != err {
+		t.Fatalf("\nError while creating directory: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "server")
+	if _, result := ezmq.LoadEZMQCertificate(filename); result != ezmq.EZMQ_ERROR {
+		t.Errorf("\nLoaded missing certificate\n")
+	}
+	ioutil.WriteFile(filename, []byte("curve\n    public-key = \"invalid\"\n"), 0644)
+	if _, result := ezmq.LoadEZMQCertificate(filename); result != ezmq.EZMQ_KEY_INVALID {
+		t.Errorf("\nLoaded invalid certificate\n")
+	}
+	if _, result := ezmq.GetEZMQCertificateFromKey([]byte("invalid")); result != ezmq.EZMQ_KEY_INVALID {
+		t.Errorf("\nCreated certificate from invalid key\n")
+	}
+}
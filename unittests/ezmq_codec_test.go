@@ -0,0 +1,139 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	ezmq "go/ezmq"
+	test_utils "go/unittests/utils"
+
+	"errors"
+	"testing"
+)
+
+const customContentType = ezmq.EZMQ_CONTENT_TYPE_APPLICATION + 300
+
+type customMessage struct {
+	text string
+}
+
+func (message customMessage) GetContentType() ezmq.EZMQContentType {
+	return customContentType
+}
+
+func marshalCustom(ezmqMsg ezmq.EZMQMessage) ([]byte, error) {
+	message, ok := ezmqMsg.(customMessage)
+	if false == ok {
+		return nil, errors.New("not a custom message")
+	}
+	return []byte(message.text), nil
+}
+
+func unmarshalCustom(data []byte) (ezmq.EZMQMessage, error) {
+	return customMessage{string(data)}, nil
+}
+
+func TestRegisterCodec(t *testing.T) {
+	if 0 != ezmq.RegisterCodec(customContentType, marshalCustom, unmarshalCustom) {
+		t.Errorf("\nError while registering codec")
+	}
+	if 0 != ezmq.UnRegisterCodec(customContentType) {
+		t.Errorf("\nError while un-registering codec")
+	}
+	if 0 == ezmq.UnRegisterCodec(customContentType) {
+		t.Errorf("\nUn-registered codec which is not registered")
+	}
+}
+
+func TestRegisterCodecNegative(t *testing.T) {
+	// built-in content types can not be replaced
+	if ezmq.EZMQ_INVALID_CONTENT_TYPE != ezmq.RegisterCodec(ezmq.EZMQ_CONTENT_TYPE_PROTOBUF, marshalCustom, unmarshalCustom) {
+		t.Errorf("\nRegistered codec for built-in content type")
+	}
+	if ezmq.EZMQ_INVALID_CONTENT_TYPE != ezmq.RegisterCodec(ezmq.EZMQ_CONTENT_TYPE_EXTENDED, marshalCustom, unmarshalCustom) {
+		t.Errorf("\nRegistered codec for reserved content type")
+	}
+	if ezmq.EZMQ_INVALID_CONTENT_TYPE != ezmq.RegisterCodec(ezmq.EZMQ_CONTENT_TYPE_APPLICATION_MAX+1, marshalCustom, unmarshalCustom) {
+		t.Errorf("\nRegistered codec for out of range content type")
+	}
+	if ezmq.EZMQ_ERROR != ezmq.RegisterCodec(customContentType, nil, unmarshalCustom) {
+		t.Errorf("\nRegistered codec without marshal function")
+	}
+	if 0 == ezmq.UnRegisterCodec(ezmq.EZMQ_CONTENT_TYPE_JSON) {
+		t.Errorf("\nUn-registered built-in codec")
+	}
+}
+
+func TestPublishUnregisteredContentType(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	customPublisher := ezmq.GetEZMQPublisher(test_utils.Port, startCB, stopCB, errorCB)
+	if nil == customPublisher || customPublisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer customPublisher.Stop()
+	if ezmq.EZMQ_INVALID_CONTENT_TYPE != customPublisher.Publish(customMessage{"custom"}) {
+		t.Errorf("\nPublished unregistered content type\n")
+	}
+}
+
+func TestCustomCodecRoundTrip(t *testing.T) {
+	if 0 != ezmq.RegisterCodec(customContentType, marshalCustom, unmarshalCustom) {
+		t.Fatalf("\nError while registering codec")
+	}
+	defer ezmq.UnRegisterCodec(customContentType)
+
+	received := make(chan ezmq.EZMQMessage, 10)
+	customSubCB := func(ezmqMsg ezmq.EZMQMessage) { received <- ezmqMsg }
+	customSubTopicCB := func(topic string, ezmqMsg ezmq.EZMQMessage) { received <- ezmqMsg }
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	customPublisher := ezmq.GetEZMQPublisher(test_utils.Port, startCB, stopCB, errorCB)
+	if nil == customPublisher || customPublisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer customPublisher.Stop()
+
+	customSubscriber := ezmq.GetEZMQSubscriber(test_utils.Ip, test_utils.Port, customSubCB, customSubTopicCB)
+	if nil == customSubscriber || customSubscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer customSubscriber.Stop()
+	if customSubscriber.Subscribe() != 0 {
+		t.Fatalf("\nError while subscribing\n")
+	}
+
+	test_utils.PublishUntilReceived(t, func() {
+		if customPublisher.Publish(customMessage{"custom"}) != 0 {
+			t.Fatalf("\nError while publishing custom message\n")
+		}
+	}, func() bool {
+		select {
+		case ezmqMsg := <-received:
+			message, ok := ezmqMsg.(customMessage)
+			if false == ok || message.text != "custom" {
+				t.Errorf("\nCustom message mismatch\n")
+			}
+			return true
+		default:
+			return false
+		}
+	})
+}
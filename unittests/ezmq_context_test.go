@@ -0,0 +1,113 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStartContextCanceled(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	err := publisher.StartContext(ctx)
+	if false == errors.Is(err, ezmq.ErrCanceled) || false == errors.Is(err, context.Canceled) {
+		t.Errorf("\nWrong error: %v\n", err)
+	}
+	if ezmq.EZMQ_NOT_STARTED != publisher.Publish(utils.GetEvent()) {
+		t.Errorf("\nPublisher started with canceled context\n")
+	}
+}
+
+func TestSubscriberStartContextTimeout(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	// no publisher on port
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err := subscriber.StartContext(ctx)
+	if false == errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("\nWrong error: %v\n", err)
+	}
+	if ezmq.EZMQ_NOT_STARTED != subscriber.Subscribe() {
+		t.Errorf("\nSubscriber not stopped after timeout\n")
+	}
+}
+
+func TestContextRoundTrip(t *testing.T) {
+	received := make(chan ezmq.EZMQMessage, 10)
+	contextSubCB := func(ezmqMsg ezmq.EZMQMessage) { received <- ezmqMsg }
+	contextSubTopicCB := func(topic string, ezmqMsg ezmq.EZMQMessage) { received <- ezmqMsg }
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	if err := publisher.StartContext(ctx); nil != err {
+		t.Fatalf("\nError while starting publisher: %v\n", err)
+	}
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, contextSubCB, contextSubTopicCB)
+	if err := subscriber.StartContext(ctx); nil != err {
+		t.Fatalf("\nError while starting subscriber: %v\n", err)
+	}
+	if ezmq.EZMQ_OK != subscriber.Subscribe() {
+		t.Fatalf("\nError while subscribing\n")
+	}
+
+	utils.PublishUntilReceived(t, func() {
+		if err := publisher.PublishContext(ctx, utils.GetEvent()); nil != err {
+			t.Errorf("\nError while publishing: %v\n", err)
+		}
+	}, func() bool {
+		select {
+		case <-received:
+			return true
+		default:
+			return false
+		}
+	})
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer stopCancel()
+	begin := time.Now()
+	if err := subscriber.StopContext(stopCtx); nil != err {
+		t.Errorf("\nError while stopping subscriber: %v\n", err)
+	}
+	if err := publisher.StopContext(stopCtx); nil != err {
+		t.Errorf("\nError while stopping publisher: %v\n", err)
+	}
+	if time.Since(begin) > time.Second {
+		t.Errorf("\nStop did not honor deadline\n")
+	}
+}
@@ -0,0 +1,125 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"testing"
+)
+
+func TestGetEndpoint(t *testing.T) {
+	validEndpoints := []string{"tcp://*:5562", "tcp://eth0:5562", "tcp://127.0.0.1:5562", "tcp://[::1]:5562",
+		"ipc:///tmp/ezmq.ipc", "inproc://ezmq"}
+	for _, uri := range validEndpoints {
+		endpoint, result := ezmq.GetEZMQEndpoint(uri)
+		if ezmq.EZMQ_OK != result || nil == endpoint {
+			t.Errorf("\nError while parsing endpoint: %s\n", uri)
+			continue
+		}
+		if endpoint.String() != uri {
+			t.Errorf("\nWrong endpoint: %s\n", endpoint.String())
+		}
+	}
+
+	invalidEndpoints := []string{"", "tcp://", "5562", "tcp://*", "tcp://:5562", "tcp://*:port", "tcp://*:70000",
+		"udp://*:5562", "inproc://"}
+	for _, uri := range invalidEndpoints {
+		endpoint, result := ezmq.GetEZMQEndpoint(uri)
+		if ezmq.EZMQ_INVALID_ENDPOINT != result || nil != endpoint {
+			t.Errorf("\nParsed invalid endpoint: %s\n", uri)
+		}
+	}
+
+	endpoint, _ := ezmq.GetEZMQEndpoint("tcp://[::1]:5562")
+	if endpoint.GetTransport() != ezmq.EZMQ_TRANSPORT_TCP || endpoint.GetHost() != "::1" || endpoint.GetPort() != 5562 {
+		t.Errorf("\nWrong endpoint fields\n")
+	}
+	endpoint, _ = ezmq.GetEZMQEndpoint("ipc:///tmp/ezmq.ipc")
+	if endpoint.GetAddress() != "/tmp/ezmq.ipc" || endpoint.GetPort() != -1 {
+		t.Errorf("\nWrong endpoint fields\n")
+	}
+	if ezmq.GetEZMQTCPEndpoint("::1", 5562).String() != "tcp://[::1]:5562" {
+		t.Errorf("\nWrong IPv6 TCP endpoint\n")
+	}
+}
+
+func TestEndpointRoundTrip(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	for _, uri := range []string{"inproc://ezmq-test", "ipc:///tmp/ezmq-test.ipc"} {
+		endpoint, _ := ezmq.GetEZMQEndpoint(uri)
+		publisher := ezmq.GetEZMQPublisherWithEndpoint(endpoint, startCB, stopCB, errorCB)
+		if nil == publisher || publisher.Start() != 0 {
+			t.Fatalf("\nError while starting publisher on %s\n", uri)
+		}
+		if publisher.GetEndpoint() != endpoint || publisher.GetPort() != -1 {
+			t.Errorf("\nWrong publisher endpoint\n")
+		}
+
+		subscriber := ezmq.GetEZMQSubscriberWithEndpoint(endpoint, nil, nil)
+		if nil == subscriber {
+			t.Fatalf("\nSubscriber instance is NULL\n")
+		}
+		messages := subscriber.GetMessageChannel(10)
+		if subscriber.Start() != 0 || subscriber.Subscribe() != 0 {
+			t.Fatalf("\nError while starting subscriber on %s\n", uri)
+		}
+		if subscriber.GetEndpoint().String() != uri || subscriber.GetIP() != "" {
+			t.Errorf("\nWrong subscriber endpoint\n")
+		}
+
+		utils.PublishUntilReceived(t, func() {
+			publisher.Publish(utils.GetEvent())
+		}, func() bool {
+			select {
+			case <-messages:
+				return true
+			default:
+				return false
+			}
+		})
+		subscriber.Stop()
+		publisher.Stop()
+	}
+}
+
+func TestSubscribeWithEndpoint(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	if nil == subscriber || subscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer subscriber.Stop()
+	endpoint, _ := ezmq.GetEZMQEndpoint("ipc:///tmp/ezmq-test.ipc")
+	if 0 != subscriber.SubscribeWithEndpoint(endpoint, utils.Topic) {
+		t.Errorf("\nError while subscribing with endpoint\n")
+	}
+	if ezmq.EZMQ_INVALID_ENDPOINT != subscriber.SubscribeWithEndpoint(nil, utils.Topic) {
+		t.Errorf("\nSubscribed with nil endpoint\n")
+	}
+	if ezmq.EZMQ_INVALID_TOPIC != subscriber.SubscribeWithEndpoint(endpoint, "") {
+		t.Errorf("\nSubscribed with invalid topic\n")
+	}
+}
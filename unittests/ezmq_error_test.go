@@ -0,0 +1,135 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"errors"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	cause := errors.New("cause")
+	err := error(&ezmq.EZMQError{Code: ezmq.EZMQ_BIND_IN_USE, Op: "bind publisher", Err: cause})
+	if false == errors.Is(err, ezmq.ErrBindInUse) {
+		t.Errorf("\nError is not ErrBindInUse\n")
+	}
+	if true == errors.Is(err, ezmq.ErrSocket) {
+		t.Errorf("\nError is ErrSocket\n")
+	}
+	if false == errors.Is(err, cause) {
+		t.Errorf("\nError does not wrap its cause\n")
+	}
+	var ezmqError *ezmq.EZMQError
+	if false == errors.As(err, &ezmqError) || ezmqError.Op != "bind publisher" {
+		t.Errorf("\nError is not an EZMQError\n")
+	}
+	if ezmq.GetErrorCode(err) != ezmq.EZMQ_BIND_IN_USE {
+		t.Errorf("\nWrong error code\n")
+	}
+	if ezmq.GetErrorCode(nil) != ezmq.EZMQ_OK {
+		t.Errorf("\nWrong error code for nil\n")
+	}
+	if ezmq.GetErrorCode(cause) != ezmq.EZMQ_ERROR {
+		t.Errorf("\nWrong error code for non EZMQ error\n")
+	}
+}
+
+func TestErrorBindInUse(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher1 := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher2 := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	if ezmq.EZMQ_OK != publisher1.Start() {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher1.Stop()
+
+	result := publisher2.Start()
+	if result != ezmq.EZMQ_BIND_IN_USE {
+		t.Errorf("\nWrong error code: %v\n", result)
+	}
+	if false == errors.Is(publisher2.GetLastError(), ezmq.ErrBindInUse) {
+		t.Errorf("\nWrong last error: %v\n", publisher2.GetLastError())
+	}
+	if nil != publisher1.GetLastError() {
+		t.Errorf("\nUnexpected last error: %v\n", publisher1.GetLastError())
+	}
+}
+
+func TestErrorNotStarted(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	result := publisher.Publish(utils.GetEvent())
+	if result != ezmq.EZMQ_NOT_STARTED {
+		t.Errorf("\nWrong error code: %v\n", result)
+	}
+	if false == errors.Is(publisher.GetLastError(), ezmq.ErrNotStarted) {
+		t.Errorf("\nWrong last error: %v\n", publisher.GetLastError())
+	}
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	result = subscriber.SubscribeForTopic(utils.Topic)
+	if result != ezmq.EZMQ_NOT_STARTED {
+		t.Errorf("\nWrong error code: %v\n", result)
+	}
+	if false == errors.Is(subscriber.GetLastError(), ezmq.ErrNotStarted) {
+		t.Errorf("\nWrong last error: %v\n", subscriber.GetLastError())
+	}
+}
+
+func TestErrorClearedOnSuccess(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.Publish(utils.GetEvent())
+	if nil == publisher.GetLastError() {
+		t.Fatalf("\nLast error is not set\n")
+	}
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+	if nil != publisher.GetLastError() {
+		t.Errorf("\nLast error is not cleared: %v\n", publisher.GetLastError())
+	}
+}
+
+func TestErrorKeyInvalid(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	result := subscriber.SetServerPublicKey([]byte("short"))
+	if result != ezmq.EZMQ_KEY_INVALID {
+		t.Errorf("\nWrong error code: %v\n", result)
+	}
+	if false == errors.Is(subscriber.GetLastError(), ezmq.ErrKeyInvalid) {
+		t.Errorf("\nWrong last error: %v\n", subscriber.GetLastError())
+	}
+}
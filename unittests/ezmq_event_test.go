@@ -0,0 +1,77 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func waitEvent(t *testing.T, events chan ezmq.EZMQEvent, eventType ezmq.EZMQEventType) ezmq.EZMQEvent {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("\nTimeout while waiting for %s event\n", eventType)
+		}
+	}
+}
+
+func TestEventCallback(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisherEvents := make(chan ezmq.EZMQEvent, 100)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.SetEventCallback(func(event ezmq.EZMQEvent) { publisherEvents <- event })
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+
+	subscriberEvents := make(chan ezmq.EZMQEvent, 100)
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	subscriber.SetEventCallback(func(event ezmq.EZMQEvent) { subscriberEvents <- event })
+	if subscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer subscriber.Stop()
+
+	event := waitEvent(t, subscriberEvents, ezmq.EZMQ_EVENT_CONNECTED)
+	if false == strings.HasSuffix(event.Endpoint, ":"+strconv.Itoa(utils.Port)) {
+		t.Errorf("\nWrong endpoint: %s\n", event.Endpoint)
+	}
+	event = waitEvent(t, publisherEvents, ezmq.EZMQ_EVENT_ACCEPTED)
+	if false == strings.HasPrefix(event.Peer, "tcp://") || event.Peer == event.Endpoint {
+		t.Errorf("\nWrong peer: %s\n", event.Peer)
+	}
+
+	// subscriber is disconnected and retries once publisher is stopped
+	publisher.Stop()
+	waitEvent(t, subscriberEvents, ezmq.EZMQ_EVENT_DISCONNECTED)
+	waitEvent(t, subscriberEvents, ezmq.EZMQ_EVENT_CONNECT_RETRIED)
+}
@@ -0,0 +1,274 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	ezmq "go/ezmq"
+	test_utils "go/unittests/utils"
+
+	zmq "github.com/pebbe/zmq4"
+
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type receivedHeader struct {
+	topic  string
+	header ezmq.EZMQHeader
+}
+
+func startHeaderPublisher(t *testing.T, port int, version int) *ezmq.EZMQPublisher {
+	headerPublisher := ezmq.GetEZMQPublisher(port, startCB, stopCB, errorCB)
+	if nil == headerPublisher {
+		t.Fatalf("\nPublisher instance is NULL\n")
+	}
+	if 0 != headerPublisher.SetHeaderVersion(version) {
+		t.Fatalf("\nError while setting header version\n")
+	}
+	if 0 != headerPublisher.Start() {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	return headerPublisher
+}
+
+func startHeaderSubscriber(t *testing.T, received chan receivedHeader) *ezmq.EZMQSubscriber {
+	headerSubscriber := ezmq.GetEZMQSubscriber(test_utils.Ip, test_utils.Port, subCB, subTopicCB)
+	if nil == headerSubscriber {
+		t.Fatalf("\nSubscriber instance is NULL\n")
+	}
+	headerSubscriber.SetSubHeaderCallback(func(topic string, header ezmq.EZMQHeader, ezmqMsg ezmq.EZMQMessage) {
+		received <- receivedHeader{topic, header}
+	})
+	if 0 != headerSubscriber.Start() {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	return headerSubscriber
+}
+
+func TestSetHeaderVersion(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	headerPublisher := ezmq.GetEZMQPublisher(test_utils.Port, startCB, stopCB, errorCB)
+	if nil == headerPublisher {
+		t.Fatalf("\nPublisher instance is NULL\n")
+	}
+	if 0 != headerPublisher.SetHeaderVersion(ezmq.EZMQ_HEADER_VERSION_1) {
+		t.Errorf("\nError while setting header version 1\n")
+	}
+	if 0 != headerPublisher.SetHeaderVersion(ezmq.EZMQ_HEADER_VERSION_2) {
+		t.Errorf("\nError while setting header version 2\n")
+	}
+	if ezmq.EZMQ_INVALID_VERSION != headerPublisher.SetHeaderVersion(3) {
+		t.Errorf("\nSet invalid header version\n")
+	}
+	if false == errors.Is(headerPublisher.GetLastError(), ezmq.ErrInvalidVersion) {
+		t.Errorf("\nWrong last error: %v\n", headerPublisher.GetLastError())
+	}
+	if 0 != headerPublisher.SetHeaderVersion(ezmq.EZMQ_HEADER_VERSION_1) {
+		t.Errorf("\nError while setting header version 1\n")
+	}
+	if nil != headerPublisher.GetLastError() {
+		t.Errorf("\nUnexpected last error: %v\n", headerPublisher.GetLastError())
+	}
+}
+
+func TestHeaderVersion2(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	received := make(chan receivedHeader, 100)
+	headerPublisher := startHeaderPublisher(t, test_utils.Port, ezmq.EZMQ_HEADER_VERSION_2)
+	defer headerPublisher.Stop()
+	headerSubscriber := startHeaderSubscriber(t, received)
+	defer headerSubscriber.Stop()
+	headerSubscriber.Subscribe()
+
+	// wait for connection
+	event := test_utils.GetEvent()
+	var first receivedHeader
+	test_utils.PublishUntilReceived(t, func() {
+		headerPublisher.PublishOnTopic(test_utils.Topic, event)
+	}, func() bool {
+		select {
+		case first = <-received:
+			return true
+		default:
+			return false
+		}
+	})
+	// drain events published while connecting
+	time.Sleep(500 * time.Millisecond)
+Drain:
+	for {
+		select {
+		case first = <-received:
+		default:
+			break Drain
+		}
+	}
+	if first.topic != test_utils.Topic || first.header.Version != ezmq.EZMQ_HEADER_VERSION_2 ||
+		first.header.ContentType != ezmq.EZMQ_CONTENT_TYPE_PROTOBUF {
+		t.Fatalf("\nHeader mismatch\n")
+	}
+	if time.Since(first.header.Timestamp) > time.Minute {
+		t.Errorf("\nInvalid timestamp\n")
+	}
+
+	// sequence number is per topic, message ID is per publisher
+	headerPublisher.Publish(test_utils.GetByteDataEvent())
+	headerPublisher.PublishOnTopic(test_utils.Topic, event)
+	var noTopic, next receivedHeader
+	for i := 0; i < 2; i++ {
+		select {
+		case header := <-received:
+			if header.topic == "" {
+				noTopic = header
+			} else {
+				next = header
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("\nTimeout while waiting for event\n")
+		}
+	}
+	if noTopic.header.SequenceNumber != 1 || noTopic.header.ContentType != ezmq.EZMQ_CONTENT_TYPE_BYTEDATA {
+		t.Errorf("\nWrong sequence number for no topic\n")
+	}
+	if next.header.SequenceNumber != first.header.SequenceNumber+1 {
+		t.Errorf("\nWrong sequence number for topic\n")
+	}
+	if next.header.MessageID != noTopic.header.MessageID+1 || next.header.PublisherID != first.header.PublisherID {
+		t.Errorf("\nWrong message ID or publisher ID\n")
+	}
+}
+
+func TestHeaderVersion1And2(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	received := make(chan receivedHeader, 100)
+	publisherV1 := startHeaderPublisher(t, test_utils.Port, ezmq.EZMQ_HEADER_VERSION_1)
+	defer publisherV1.Stop()
+	publisherV2 := startHeaderPublisher(t, test_utils.Port+1, ezmq.EZMQ_HEADER_VERSION_2)
+	defer publisherV2.Stop()
+	headerSubscriber := startHeaderSubscriber(t, received)
+	defer headerSubscriber.Stop()
+	headerSubscriber.SubscribeForTopic(test_utils.Topic)
+	headerSubscriber.SubscribeWithIPPort(test_utils.Ip, test_utils.Port+1, test_utils.Topic)
+
+	event := test_utils.GetEvent()
+	versions := make(map[int]bool)
+	test_utils.PublishUntilReceived(t, func() {
+		publisherV1.PublishOnTopic(test_utils.Topic, event)
+		publisherV2.PublishOnTopic(test_utils.Topic, event)
+	}, func() bool {
+		for {
+			select {
+			case header := <-received:
+				versions[header.header.Version] = true
+				if header.header.Version == ezmq.EZMQ_HEADER_VERSION_1 && header.header.SequenceNumber != 0 {
+					t.Errorf("\nVersion 1 header with sequence number\n")
+				}
+			default:
+				return len(versions) == 2
+			}
+		}
+	})
+}
+
+func TestUnknownHeaderVersion(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	received := make(chan receivedHeader, 100)
+	rawPublisher, err := zmq.NewSocket(zmq.PUB)
+	if nil != err {
+		t.Fatalf("\nError while creating socket\n")
+	}
+	defer rawPublisher.Close()
+	rawPublisher.SetLinger(0)
+	if nil != rawPublisher.Bind("tcp://*:"+strconv.Itoa(test_utils.Port)) {
+		t.Fatalf("\nError while binding socket\n")
+	}
+	headerSubscriber := startHeaderSubscriber(t, received)
+	defer headerSubscriber.Stop()
+	headerSubscriber.Subscribe()
+
+	// content type: byte data, version: 7
+	unknownHeader := []byte{(1 << 5) | (7 << 2)}
+	// content type: byte data, version: 1
+	knownHeader := []byte{(1 << 5) | (1 << 2)}
+	test_utils.PublishUntilReceived(t, func() {
+		rawPublisher.SendBytes(unknownHeader, zmq.SNDMORE)
+		rawPublisher.SendBytes([]byte{0x01}, 0)
+		rawPublisher.SendBytes(knownHeader, zmq.SNDMORE)
+		rawPublisher.SendBytes([]byte{0x02}, 0)
+	}, func() bool {
+		select {
+		case header := <-received:
+			if header.header.Version != ezmq.EZMQ_HEADER_VERSION_1 {
+				t.Fatalf("\nReceived event with unknown header version\n")
+			}
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+func TestUnknownHeaderVersionReported(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	rawPublisher, err := zmq.NewSocket(zmq.PUB)
+	if nil != err {
+		t.Fatalf("\nError while creating socket\n")
+	}
+	defer rawPublisher.Close()
+	rawPublisher.SetLinger(0)
+	if nil != rawPublisher.Bind("tcp://*:"+strconv.Itoa(test_utils.Port)) {
+		t.Fatalf("\nError while binding socket\n")
+	}
+	errors := make(chan ezmq.EZMQErrorCode, 100)
+	headerSubscriber := ezmq.GetEZMQSubscriber(test_utils.Ip, test_utils.Port, subCB, subTopicCB)
+	headerSubscriber.SetStatusCallbacks(nil, nil, func(code ezmq.EZMQErrorCode) { errors <- code })
+	if 0 != headerSubscriber.Start() {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer headerSubscriber.Stop()
+	headerSubscriber.Subscribe()
+
+	// content type: byte data, version: 3
+	unknownHeader := []byte{(1 << 5) | (3 << 2)}
+	test_utils.PublishUntilReceived(t, func() {
+		rawPublisher.SendBytes(unknownHeader, zmq.SNDMORE)
+		rawPublisher.SendBytes([]byte{0x01}, 0)
+	}, func() bool {
+		select {
+		case code := <-errors:
+			if code != ezmq.EZMQ_INVALID_VERSION {
+				t.Fatalf("\nWrong error code: %d\n", code)
+			}
+			return true
+		default:
+			return false
+		}
+	})
+}
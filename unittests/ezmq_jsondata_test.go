@@ -0,0 +1,124 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"bytes"
+	ezmq "go/ezmq"
+	test_utils "go/unittests/utils"
+
+	"testing"
+)
+
+func TestGetJSONData(t *testing.T) {
+	jsonData := test_utils.GetJSONDataEvent()
+	var array []byte = []byte(`{"device":"device1","value":"20"}`)
+	if false == bytes.Equal(array, jsonData.GetJSONData()) {
+		t.Errorf("\nAssertion failed")
+	}
+}
+
+func TestSetJSONData(t *testing.T) {
+	var jsonData ezmq.EZMQJSONData
+	var array []byte = []byte(`{"device":"device2"}`)
+	if 0 != jsonData.SetJSONData(array) {
+		t.Errorf("\nError while setting JSON data")
+	}
+	if false == bytes.Equal(array, jsonData.GetJSONData()) {
+		t.Errorf("\nAssertion failed")
+	}
+}
+
+func TestSetInvalidJSONData(t *testing.T) {
+	var jsonData ezmq.EZMQJSONData
+	if 0 == jsonData.SetJSONData(nil) {
+		t.Errorf("\nAssertion failed")
+	}
+	if 0 == jsonData.SetJSONData([]byte(`{"device":`)) {
+		t.Errorf("\nAssertion failed")
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	type reading struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	var jsonData ezmq.EZMQJSONData
+	if 0 != jsonData.SetValue(reading{"temperature", "20"}) {
+		t.Errorf("\nError while setting value")
+	}
+	// value is serialized only on publish
+	var converted reading
+	if 0 == jsonData.GetValue(&converted) {
+		t.Errorf("\nAssertion failed")
+	}
+
+	jsonData.JSONData = []byte(`{"name":"temperature","value":"20"}`)
+	if 0 != jsonData.GetValue(&converted) {
+		t.Errorf("\nError while getting value")
+	}
+	if converted.Name != "temperature" || converted.Value != "20" {
+		t.Errorf("\nValue mismatch")
+	}
+}
+
+func TestJSONEvent(t *testing.T) {
+	var event ezmq.Event = test_utils.GetEvent()
+	var jsonData ezmq.EZMQJSONData
+	if 0 != jsonData.SetEvent(event) {
+		t.Errorf("\nError while setting event")
+	}
+	convertedEvent, result := jsonData.GetEvent()
+	if 0 != result {
+		t.Errorf("\nError while getting event")
+	}
+	if event.String() != convertedEvent.String() {
+		t.Errorf("\nEvent string mismatch")
+	}
+
+	// missing required fields
+	jsonData.JSONData = []byte(`{"device":"device1"}`)
+	_, result = jsonData.GetEvent()
+	if 0 == result {
+		t.Errorf("\nAssertion failed")
+	}
+}
+
+func TestJSONReading(t *testing.T) {
+	var event ezmq.Event = test_utils.GetEvent()
+	var reading *ezmq.Reading = event.GetReading()[0]
+	var jsonData ezmq.EZMQJSONData
+	if 0 != jsonData.SetReading(*reading) {
+		t.Errorf("\nError while setting reading")
+	}
+	convertedReading, result := jsonData.GetReading()
+	if 0 != result {
+		t.Errorf("\nError while getting reading")
+	}
+	if reading.String() != convertedReading.String() {
+		t.Errorf("\nReading string mismatch")
+	}
+}
+
+func TestJSONDataContentType(t *testing.T) {
+	jsonData := test_utils.GetJSONDataEvent()
+	if 3 != jsonData.GetContentType() {
+		t.Errorf("\nAssertion failed")
+	}
+}
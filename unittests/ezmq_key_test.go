@@ -0,0 +1,162 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	zmq "github.com/pebbe/zmq4"
+
+	"testing"
+)
+
+func TestPublisherKeyWipe(t *testing.T) {
+	_, serverSecretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	key := []byte(serverSecretKey)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.SetServerPrivateKey(key)
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	if string(key) != serverSecretKey {
+		t.Errorf("\nKey of application is changed\n")
+	}
+	publisher.Stop()
+
+	// secured publisher is not restarted in unsecured mode once key is wiped
+	if ezmq.EZMQ_KEY_INVALID != publisher.Start() {
+		t.Errorf("\nPublisher started without key\n")
+	}
+	publisher.SetServerPrivateKey(key)
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while restarting publisher: %v\n", publisher.GetLastError())
+	}
+	if publisher.Close() != 0 {
+		t.Errorf("\nError while closing publisher: %v\n", publisher.GetLastError())
+	}
+	if publisher.Close() != 0 {
+		t.Errorf("\nError while closing publisher twice\n")
+	}
+}
+
+func TestSubscriberKeyWipe(t *testing.T) {
+	serverPublicKey, _, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	clientPublicKey, clientSecretKey, _ := zmq.NewCurveKeypair()
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	subscriber.SetClientKeys([]byte(clientSecretKey), []byte(clientPublicKey))
+	subscriber.SetServerPublicKey([]byte(serverPublicKey))
+	if subscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	// server key of other publisher is applied without client private key
+	if subscriber.SubscribeWithIPPort(utils.Ip, utils.Port+1, utils.Topic) != 0 {
+		t.Errorf("\nError while subscribing: %v\n", subscriber.GetLastError())
+	}
+	subscriber.Stop()
+
+	if ezmq.EZMQ_KEY_INVALID != subscriber.Start() {
+		t.Errorf("\nSubscriber started without key\n")
+	}
+	if subscriber.Close() != 0 {
+		t.Errorf("\nError while closing subscriber: %v\n", subscriber.GetLastError())
+	}
+}
+
+func TestSubscriberKeyWipeServerKeyAfterStart(t *testing.T) {
+	serverPublicKey, _, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	clientPublicKey, clientSecretKey, _ := zmq.NewCurveKeypair()
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	// server key is set after start and applied on subscribing with ip port
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	subscriber.SetClientKeys([]byte(clientSecretKey), []byte(clientPublicKey))
+	if subscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	subscriber.SetServerPublicKey([]byte(serverPublicKey))
+	if subscriber.SubscribeWithIPPort(utils.Ip, utils.Port+1, utils.Topic) != 0 {
+		t.Errorf("\nError while subscribing: %v\n", subscriber.GetLastError())
+	}
+	if subscriber.Close() != 0 {
+		t.Errorf("\nError while closing subscriber: %v\n", subscriber.GetLastError())
+	}
+}
+
+func TestCertificateKeyWipe(t *testing.T) {
+	cert, result := ezmq.GetEZMQCertificate()
+	if result != ezmq.EZMQ_OK {
+		t.Skip("CURVE is not supported")
+	}
+	secretKey := cert.GetSecretKey()
+	cert.Close()
+	if nil != cert.GetSecretKey() {
+		t.Errorf("\nSecret key is not removed\n")
+	}
+	for _, value := range secretKey {
+		if 0 != value {
+			t.Fatalf("\nSecret key is not wiped\n")
+		}
+	}
+}
+
+func TestInvalidZ85Key(t *testing.T) {
+	publicKey, secretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	// 40 characters, but not Z85 encoded
+	invalidKey := []byte("~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~")
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	if ezmq.EZMQ_KEY_INVALID != publisher.SetServerPrivateKey(invalidKey) {
+		t.Errorf("\nInvalid server private key accepted\n")
+	}
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	if ezmq.EZMQ_KEY_INVALID != subscriber.SetClientKeys(invalidKey, []byte(publicKey)) {
+		t.Errorf("\nInvalid client private key accepted\n")
+	}
+	if ezmq.EZMQ_KEY_INVALID != subscriber.SetClientKeys([]byte(secretKey), invalidKey) {
+		t.Errorf("\nInvalid client public key accepted\n")
+	}
+	if ezmq.EZMQ_KEY_INVALID != subscriber.SetServerPublicKey(invalidKey) {
+		t.Errorf("\nInvalid server public key accepted\n")
+	}
+}
@@ -0,0 +1,137 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	ezmq "go/ezmq"
+	test_utils "go/unittests/utils"
+
+	zmq "github.com/pebbe/zmq4"
+
+	"encoding/binary"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Form version 2 header for byte data.
+func getV2Header(publisherID uint64, sequence uint64) []byte {
+	header := make([]byte, 33)
+	header[0] = (1 << 5) | (2 << 2)
+	binary.BigEndian.PutUint64(header[1:], publisherID)
+	binary.BigEndian.PutUint64(header[9:], sequence)
+	binary.BigEndian.PutUint64(header[17:], uint64(time.Now().UnixNano()))
+	binary.BigEndian.PutUint64(header[25:], sequence)
+	return header
+}
+
+func TestLossDetection(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	rawPublisher, err := zmq.NewSocket(zmq.PUB)
+	if nil != err {
+		t.Fatalf("\nError while creating socket\n")
+	}
+	defer rawPublisher.Close()
+	rawPublisher.SetLinger(0)
+	if nil != rawPublisher.Bind("tcp://*:"+strconv.Itoa(test_utils.Port)) {
+		t.Fatalf("\nError while binding socket\n")
+	}
+
+	received := make(chan ezmq.EZMQHeader, 100)
+	reports := make(chan ezmq.EZMQLossReport, 100)
+	lossSubscriber := ezmq.GetEZMQSubscriber(test_utils.Ip, test_utils.Port, subCB, subTopicCB)
+	if nil == lossSubscriber {
+		t.Fatalf("\nSubscriber instance is NULL\n")
+	}
+	lossSubscriber.SetSubHeaderCallback(func(topic string, header ezmq.EZMQHeader, ezmqMsg ezmq.EZMQMessage) {
+		received <- header
+	})
+	lossSubscriber.SetLossCallback(func(report ezmq.EZMQLossReport) {
+		reports <- report
+	})
+	if 0 != lossSubscriber.Start() {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer lossSubscriber.Stop()
+	lossSubscriber.SubscribeForTopic(test_utils.Topic)
+
+	send := func(publisherID uint64, sequence uint64) {
+		rawPublisher.Send(test_utils.Topic+"/", zmq.SNDMORE)
+		rawPublisher.SendBytes(getV2Header(publisherID, sequence), zmq.SNDMORE)
+		rawPublisher.SendBytes([]byte{0x01}, 0)
+	}
+
+	// wait for connection using a different publisher ID
+	test_utils.PublishUntilReceived(t, func() {
+		send(1, 1)
+	}, func() bool {
+		select {
+		case <-received:
+			return true
+		default:
+			return false
+		}
+	})
+
+	for _, sequence := range []uint64{1, 2, 5, 5, 6} {
+		send(2, sequence)
+	}
+	for count := 0; count < 5; {
+		select {
+		case header := <-received:
+			if header.PublisherID == 2 {
+				count++
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("\nTimeout while waiting for events\n")
+		}
+	}
+
+	var gap, duplicate ezmq.EZMQLossReport
+	for count := 0; count < 2; {
+		select {
+		case report := <-reports:
+			if report.PublisherID != 2 {
+				continue
+			}
+			count++
+			if report.IsDuplicate {
+				duplicate = report
+			} else {
+				gap = report
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("\nTimeout while waiting for loss report\n")
+		}
+	}
+	if gap.Topic != test_utils.Topic || gap.ExpectedSequence != 3 || gap.ReceivedSequence != 5 || gap.Lost != 2 {
+		t.Errorf("\nWrong gap report\n")
+	}
+	if duplicate.ReceivedSequence != 5 || duplicate.TotalLost != 2 || duplicate.TotalDuplicates != 1 {
+		t.Errorf("\nWrong duplicate report\n")
+	}
+	select {
+	case report := <-reports:
+		if report.PublisherID == 2 {
+			t.Errorf("\nUnexpected loss report\n")
+		}
+	default:
+	}
+}
@@ -0,0 +1,62 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"testing"
+	"time"
+)
+
+func TestSocketOptions(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB, ezmq.WithSendHWM(10000),
+		ezmq.WithLinger(100*time.Millisecond), ezmq.WithTCPKeepAlive(30*time.Second, 5*time.Second, 3))
+	if ezmq.EZMQ_OK != publisher.Start() {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB, ezmq.WithReceiveHWM(10),
+		ezmq.WithReconnectInterval(100*time.Millisecond, time.Second))
+	if ezmq.EZMQ_OK != subscriber.Start() {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+}
+
+func TestSocketOptionsNegative(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB, ezmq.WithSendHWM(-1))
+	if ezmq.EZMQ_SOCKET_ERROR != publisher.Start() {
+		t.Errorf("\nStarted publisher with invalid high water mark\n")
+	}
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB, ezmq.WithReceiveHWM(-1))
+	if ezmq.EZMQ_SOCKET_ERROR != subscriber.Start() {
+		t.Errorf("\nStarted subscriber with invalid high water mark\n")
+	}
+	subscriber.Stop()
+}
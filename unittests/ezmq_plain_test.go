@@ -0,0 +1,89 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"testing"
+	"time"
+)
+
+func TestPlainAuthentication(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	store := ezmq.GetEZMQPasswordStore()
+	store.AddUser("admin", []byte("secret"))
+	failures := make(chan ezmq.EZMQAuthFailure, 10)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.SetCredentialStore(store)
+	publisher.SetAuthFailureCallback(func(failure ezmq.EZMQAuthFailure) { failures <- failure })
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	subscriber.SetCredentials("admin", []byte("secret"))
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+	receiveReverse(t, publisher, messages)
+
+	denied := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	denied.SetCredentials("admin", []byte("wrong"))
+	if denied.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", denied.GetLastError())
+	}
+	defer denied.Stop()
+	select {
+	case failure := <-failures:
+		if failure.Mechanism != "PLAIN" || failure.UserID != "admin" {
+			t.Errorf("\nWrong failure: %+v\n", failure)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("\nTimeout while waiting for authentication failure\n")
+	}
+}
+
+func TestPlainAuthenticationNegative(t *testing.T) {
+	store := ezmq.GetEZMQPasswordStore()
+	if ezmq.EZMQ_ERROR != store.AddUser("", []byte("secret")) {
+		t.Errorf("\nEmpty user name accepted\n")
+	}
+	store.AddUser("admin", []byte("secret"))
+	if false == store.Authenticate("admin", []byte("secret")) || store.Authenticate("admin", []byte("wrong")) {
+		t.Errorf("\nWrong authentication result\n")
+	}
+	if store.RemoveUser("admin") != 0 || ezmq.EZMQ_ERROR != store.RemoveUser("admin") {
+		t.Errorf("\nError while removing user\n")
+	}
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	if ezmq.EZMQ_ERROR != subscriber.SetCredentials("", []byte("secret")) {
+		t.Errorf("\nEmpty user name accepted\n")
+	}
+}
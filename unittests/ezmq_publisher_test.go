@@ -22,6 +22,7 @@ import (
 	"go/unittests/utils"
 
 	List "container/list"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -94,6 +95,20 @@ func TestPublish1(t *testing.T) {
 		t.Errorf("\nError while publishing event\n")
 	}
 
+	jsonData := utils.GetJSONDataEvent()
+	pubResult = publisher.Publish(jsonData)
+	if pubResult != 0 {
+		t.Errorf("\nError while publishing JSON data\n")
+	}
+	pubResult = publisher.Publish(&jsonData)
+	if pubResult != 0 {
+		t.Errorf("\nError while publishing JSON data pointer\n")
+	}
+	pubResult = publisher.Publish(ezmq.EZMQJSONData{})
+	if pubResult != ezmq.EZMQ_SERIALIZATION_FAILED || nil == errors.Unwrap(publisher.GetLastError()) {
+		t.Errorf("\nEmpty JSON data published: %v\n", publisher.GetLastError())
+	}
+
 	pubResult = publisher.Stop()
 	if pubResult != 0 {
 		t.Errorf("\nError while Stopping publisher")
@@ -124,6 +139,12 @@ func TestPublish2(t *testing.T) {
 	if pubResult != 0 {
 		t.Errorf("\nError while publishing byte data on utils.Topic\n")
 	}
+
+	jsonData := utils.GetJSONDataEvent()
+	pubResult = publisher.PublishOnTopic(utils.Topic, jsonData)
+	if pubResult != 0 {
+		t.Errorf("\nError while publishing JSON data on utils.Topic\n")
+	}
 	pubResult = publisher.Stop()
 	if pubResult != 0 {
 		t.Errorf("\nError while Stopping publisher")
@@ -198,7 +219,7 @@ func TestPublishSecured(t *testing.T) {
 
 	//negative case
 	pubResult = publisher.SetServerPrivateKey([]byte(""))
-	if pubResult != ezmq.EZMQ_ERROR {
+	if pubResult != ezmq.EZMQ_KEY_INVALID {
 		t.Errorf("\nWrong error code\n")
 	}
 
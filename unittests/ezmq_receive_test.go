@@ -0,0 +1,102 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"testing"
+	"time"
+)
+
+func TestMessageChannel(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	if nil == publisher || publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	messages := subscriber.GetMessageChannel(10)
+	if messages != subscriber.GetMessageChannel(20) {
+		t.Errorf("\nDifferent channel returned\n")
+	}
+	if subscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer subscriber.Stop()
+	if subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while subscribing\n")
+	}
+
+	utils.PublishUntilReceived(t, func() {
+		publisher.PublishOnTopic(utils.Topic, utils.GetEvent())
+	}, func() bool {
+		select {
+		case message := <-messages:
+			if message.Topic != utils.Topic {
+				t.Errorf("\nWrong topic: %s\n", message.Topic)
+			}
+			if message.ContentType != ezmq.EZMQ_CONTENT_TYPE_PROTOBUF {
+				t.Errorf("\nWrong content type: %d\n", message.ContentType)
+			}
+			if _, ok := message.Message.(ezmq.Event); false == ok {
+				t.Errorf("\nWrong message type\n")
+			}
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+func TestMessageChannelFullStop(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	if nil == publisher || publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher.Stop()
+
+	// channel is never read
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	subscriber.GetMessageChannel(0)
+	if subscriber.Start() != 0 || subscriber.Subscribe() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	for i := 0; i < 10; i++ {
+		publisher.Publish(utils.GetEvent())
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	begin := time.Now()
+	if subscriber.Stop() != 0 {
+		t.Errorf("\nError while stopping subscriber\n")
+	}
+	if time.Since(begin) > 2*time.Second {
+		t.Errorf("\nStop blocked on full channel\n")
+	}
+}
@@ -0,0 +1,123 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	zmq "github.com/pebbe/zmq4"
+
+	"context"
+	"testing"
+	"time"
+)
+
+func receiveReverse(t *testing.T, publisher *ezmq.EZMQPublisher, messages <-chan ezmq.EZMQReceivedMessage) {
+	t.Helper()
+	utils.PublishUntilReceived(t, func() {
+		publisher.PublishOnTopic(utils.Topic, utils.GetEvent())
+	}, func() bool {
+		select {
+		case message := <-messages:
+			if message.Topic != utils.Topic {
+				t.Errorf("\nWrong topic: %s\n", message.Topic)
+			}
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+func TestReverseTopology(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	subscriber := ezmq.GetEZMQSubscriberWithEndpoint(ezmq.GetEZMQTCPEndpoint("*", utils.Port), nil, nil,
+		ezmq.WithReverseTopology())
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+
+	// many publishers connect to one subscriber
+	for i := 0; i < 2; i++ {
+		publisher := ezmq.GetEZMQPublisherWithEndpoint(ezmq.GetEZMQTCPEndpoint(utils.Ip, utils.Port), startCB,
+			stopCB, errorCB, ezmq.WithReverseTopology())
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := publisher.StartContext(ctx)
+		cancel()
+		if nil != err {
+			t.Fatalf("\nError while starting publisher: %v\n", err)
+		}
+		receiveReverse(t, publisher, messages)
+		publisher.Stop()
+	}
+}
+
+func TestReverseTopologyBindInUse(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	endpoint := ezmq.GetEZMQTCPEndpoint("*", utils.Port)
+	subscriber1 := ezmq.GetEZMQSubscriberWithEndpoint(endpoint, subCB, subTopicCB, ezmq.WithReverseTopology())
+	subscriber2 := ezmq.GetEZMQSubscriberWithEndpoint(endpoint, subCB, subTopicCB, ezmq.WithReverseTopology())
+	if subscriber1.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer subscriber1.Stop()
+	if ezmq.EZMQ_BIND_IN_USE != subscriber2.Start() {
+		t.Errorf("\nStarted two subscribers on same port\n")
+	}
+	subscriber2.Stop()
+}
+
+func TestReverseTopologySecured(t *testing.T) {
+	serverPublicKey, serverSecretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	clientPublicKey, clientSecretKey, _ := zmq.NewCurveKeypair()
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	subscriber := ezmq.GetEZMQSubscriberWithEndpoint(ezmq.GetEZMQTCPEndpoint("*", utils.Port), nil, nil,
+		ezmq.WithReverseTopology())
+	subscriber.SetClientKeys([]byte(clientSecretKey), []byte(clientPublicKey))
+	subscriber.SetServerPublicKey([]byte(serverPublicKey))
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+
+	publisher := ezmq.GetEZMQPublisherWithEndpoint(ezmq.GetEZMQTCPEndpoint(utils.Ip, utils.Port), startCB,
+		stopCB, errorCB, ezmq.WithReverseTopology())
+	publisher.SetServerPrivateKey([]byte(serverSecretKey))
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+	receiveReverse(t, publisher, messages)
+}
@@ -0,0 +1,84 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	zmq "github.com/pebbe/zmq4"
+
+	"testing"
+)
+
+func TestKeyRotation(t *testing.T) {
+	serverPublicKey, serverSecretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	rotatedPublicKey, rotatedSecretKey, _ := zmq.NewCurveKeypair()
+	clientPublicKey, clientSecretKey, _ := zmq.NewCurveKeypair()
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.SetServerPrivateKey([]byte(serverSecretKey))
+	rotatedEndpoint := ezmq.GetEZMQTCPEndpoint("*", utils.Port+1)
+	if ezmq.EZMQ_NOT_STARTED != publisher.StartKeyRotation([]byte(rotatedSecretKey), rotatedEndpoint) {
+		t.Errorf("\nRotation started before publisher\n")
+	}
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	subscriber.SetClientKeys([]byte(clientSecretKey), []byte(clientPublicKey))
+	subscriber.SetServerPublicKey([]byte(serverPublicKey))
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+	receiveReverse(t, publisher, messages)
+
+	if ezmq.EZMQ_ERROR != publisher.FinishKeyRotation() {
+		t.Errorf("\nRotation finished before it is started\n")
+	}
+	if ezmq.EZMQ_KEY_INVALID != publisher.StartKeyRotation([]byte("invalid"), rotatedEndpoint) {
+		t.Errorf("\nInvalid key accepted\n")
+	}
+	if publisher.StartKeyRotation([]byte(rotatedSecretKey), rotatedEndpoint) != 0 {
+		t.Fatalf("\nError while starting key rotation: %v\n", publisher.GetLastError())
+	}
+	if subscriber.RotateServerKey([]byte(rotatedPublicKey), ezmq.GetEZMQTCPEndpoint(utils.Ip, utils.Port+1)) != 0 {
+		t.Fatalf("\nError while rotating server key: %v\n", subscriber.GetLastError())
+	}
+	// subscription is kept with new key
+	receiveReverse(t, publisher, messages)
+
+	if publisher.FinishKeyRotation() != 0 {
+		t.Fatalf("\nError while finishing key rotation: %v\n", publisher.GetLastError())
+	}
+	if publisher.GetPort() != utils.Port+1 {
+		t.Errorf("\nWrong port after rotation: %d\n", publisher.GetPort())
+	}
+	receiveReverse(t, publisher, messages)
+}
@@ -0,0 +1,63 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	zmq "github.com/pebbe/zmq4"
+
+	"testing"
+)
+
+func TestMixedSecurityModes(t *testing.T) {
+	serverPublicKey, serverSecretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	clientPublicKey, clientSecretKey, _ := zmq.NewCurveKeypair()
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	secured := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	secured.SetServerPrivateKey([]byte(serverSecretKey))
+	unsecured := ezmq.GetEZMQPublisher(utils.Port+1, startCB, stopCB, errorCB)
+	if secured.Start() != 0 || unsecured.Start() != 0 {
+		t.Fatalf("\nError while starting publishers\n")
+	}
+	defer secured.Stop()
+	defer unsecured.Stop()
+
+	securedSubscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	securedSubscriber.SetClientKeys([]byte(clientSecretKey), []byte(clientPublicKey))
+	securedSubscriber.SetServerPublicKey([]byte(serverPublicKey))
+	securedMessages := securedSubscriber.GetMessageChannel(10)
+	unsecuredSubscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port+1, nil, nil)
+	unsecuredMessages := unsecuredSubscriber.GetMessageChannel(10)
+	for _, subscriber := range []*ezmq.EZMQSubscriber{securedSubscriber, unsecuredSubscriber} {
+		if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+			t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+		}
+		defer subscriber.Stop()
+	}
+	receiveReverse(t, secured, securedMessages)
+	receiveReverse(t, unsecured, unsecuredMessages)
+}
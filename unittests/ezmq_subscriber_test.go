@@ -323,11 +323,11 @@ func TestSubscribeSecured(t *testing.T) {
 
 	//Negative case
 	subResult = subscriber.SetClientKeys([]byte(""), []byte(""))
-	if subResult != ezmq.EZMQ_ERROR {
+	if subResult != ezmq.EZMQ_KEY_INVALID {
 		t.Errorf("\nError while setting client keys\n")
 	}
 	subResult = subscriber.SetServerPublicKey([]byte(""))
-	if subResult != ezmq.EZMQ_ERROR {
+	if subResult != ezmq.EZMQ_KEY_INVALID {
 		t.Errorf("\nError while setting server key\n")
 	}
 
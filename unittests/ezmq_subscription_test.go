@@ -0,0 +1,143 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"testing"
+	"time"
+)
+
+func waitSubscription(t *testing.T, changes chan bool, subscribed bool) {
+	select {
+	case change := <-changes:
+		if change != subscribed {
+			t.Errorf("\nWrong subscription change: %t\n", change)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("\nTimeout while waiting subscription change\n")
+	}
+}
+
+func TestPublisherSubscriptions(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	changes := make(chan bool, 10)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.SetSubscriptionCallback(func(topic string, subscribed bool) {
+		if topic != utils.Topic {
+			t.Errorf("\nWrong topic: %s\n", topic)
+		}
+		changes <- subscribed
+	})
+	if false != publisher.HasSubscribers(utils.Topic) {
+		t.Errorf("\nSubscribers found without start\n")
+	}
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher.Stop()
+	if false != publisher.HasSubscribers(utils.Topic) {
+		t.Errorf("\nSubscribers found without subscriber\n")
+	}
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while subscribing\n")
+	}
+	defer subscriber.Stop()
+	waitSubscription(t, changes, true)
+	if false == publisher.HasSubscribers(utils.Topic) || false == publisher.HasSubscribers(utils.Topic+"/sub") {
+		t.Errorf("\nSubscribers not found\n")
+	}
+	if false != publisher.HasSubscribers("other") {
+		t.Errorf("\nSubscribers found for other topic\n")
+	}
+
+	if subscriber.UnSubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while unsubscribing\n")
+	}
+	waitSubscription(t, changes, false)
+	if false != publisher.HasSubscribers(utils.Topic) {
+		t.Errorf("\nSubscribers found after unsubscribe\n")
+	}
+}
+
+func TestSubscriptionTrackerRestart(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	changes := make(chan bool, 10)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.SetSubscriptionCallback(func(topic string, subscribed bool) {
+		changes <- subscribed
+	})
+	// tracker is stopped with publisher and started again
+	if publisher.Start() != 0 || publisher.Stop() != 0 || publisher.Start() != 0 {
+		t.Fatalf("\nError while restarting publisher\n")
+	}
+	defer publisher.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while subscribing\n")
+	}
+	defer subscriber.Stop()
+	waitSubscription(t, changes, true)
+}
+
+func TestSkipUnsubscribedTopics(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB, ezmq.WithSkipUnsubscribedTopics())
+	if ezmq.EZMQ_NOT_STARTED != publisher.PublishOnTopic(utils.Topic, utils.GetEvent()) {
+		t.Errorf("\nPublished without start\n")
+	}
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher.Stop()
+	if publisher.PublishOnTopic(utils.Topic, utils.GetEvent()) != 0 {
+		t.Errorf("\nError while publishing without subscriber\n")
+	}
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while subscribing\n")
+	}
+	defer subscriber.Stop()
+
+	utils.PublishUntilReceived(t, func() {
+		publisher.PublishOnTopic(utils.Topic, utils.GetEvent())
+	}, func() bool {
+		select {
+		case <-messages:
+			return true
+		default:
+			return false
+		}
+	})
+}
@@ -0,0 +1,101 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"testing"
+)
+
+// Publish all the topics until wanted topic is received, fails on receiving
+// any other topic.
+func receiveWildcard(t *testing.T, pattern string, wanted string, topics []string) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	if nil == publisher || publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	messages := subscriber.GetMessageChannel(100)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(pattern) != 0 {
+		t.Fatalf("\nError while subscribing: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+
+	utils.PublishUntilReceived(t, func() {
+		for _, topic := range topics {
+			publisher.PublishOnTopic(topic, utils.GetEvent())
+		}
+		publisher.PublishOnTopic(wanted, utils.GetEvent())
+	}, func() bool {
+		select {
+		case message := <-messages:
+			if message.Topic != wanted {
+				t.Fatalf("\nWrong topic for %s: %s\n", pattern, message.Topic)
+			}
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+func TestSubscribeSingleLevelWildcard(t *testing.T) {
+	receiveWildcard(t, "home/+/temperature", "home/livingroom/temperature",
+		[]string{"home/livingroom/humidity", "home/kitchen/temperature/sensor", "office/livingroom/temperature"})
+}
+
+func TestSubscribeMultiLevelWildcard(t *testing.T) {
+	receiveWildcard(t, "home/livingroom/#", "home/livingroom/temperature/sensor",
+		[]string{"home/kitchen/temperature", "home/livingroom2/temperature"})
+}
+
+func TestSubscribeWildcardFirstLevel(t *testing.T) {
+	receiveWildcard(t, "+/livingroom/temperature", "office/livingroom/temperature",
+		[]string{"home/livingroom/humidity", "livingroom/temperature"})
+}
+
+func TestSubscribeInvalidWildcard(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	if subscriber.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber\n")
+	}
+	defer subscriber.Stop()
+	for _, topic := range []string{"home/#/temperature", "home/living+/temperature", "home//+", "home/te#"} {
+		if ezmq.EZMQ_INVALID_TOPIC != subscriber.SubscribeForTopic(topic) {
+			t.Errorf("\nSubscribed for invalid topic: %s\n", topic)
+		}
+	}
+	if subscriber.SubscribeForTopic("home/+/temperature/") != 0 {
+		t.Errorf("\nError while subscribing with trailing slash\n")
+	}
+	if subscriber.UnSubscribeForTopic("home/+/temperature") != 0 {
+		t.Errorf("\nError while unsubscribing\n")
+	}
+}
@@ -0,0 +1,185 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	"testing"
+	"time"
+)
+
+// Publish on topic until it is received on one of the channels. Returns true
+// if it is received on expected channel. Messages of previous topics are
+// ignored.
+func routeTopic(t *testing.T, publisher *ezmq.EZMQPublisher, topic string, expected chan string,
+	other chan string) bool {
+	var result bool
+	utils.PublishUntilReceived(t, func() {
+		publisher.PublishOnTopic(topic, utils.GetEvent())
+	}, func() bool {
+		for {
+			select {
+			case received := <-expected:
+				if received == topic {
+					result = true
+					return true
+				}
+			case received := <-other:
+				if received == topic {
+					result = false
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	})
+	return result
+}
+
+func TestTopicHandler(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	if nil == publisher || publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher.Stop()
+
+	defaultTopics := make(chan string, 100)
+	handlerTopics := make(chan string, 100)
+	defaultCB := func(topic string, event ezmq.EZMQMessage) { defaultTopics <- topic }
+	handler := func(topic string, event ezmq.EZMQMessage) { handlerTopics <- topic }
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, defaultCB)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic("home") != 0 {
+		t.Fatalf("\nError while subscribing\n")
+	}
+	defer subscriber.Stop()
+	id, result := subscriber.SubscribeForTopicWithHandler("home/+/temperature", handler)
+	if result != 0 {
+		t.Fatalf("\nError while subscribing with handler\n")
+	}
+
+	if false == routeTopic(t, publisher, "home/livingroom/temperature", handlerTopics, defaultTopics) {
+		t.Errorf("\nMessage not delivered to handler\n")
+	}
+	if false == routeTopic(t, publisher, "home/livingroom/humidity", defaultTopics, handlerTopics) {
+		t.Errorf("\nMessage not delivered to default callback\n")
+	}
+
+	if subscriber.RemoveTopicHandler(id) != 0 {
+		t.Errorf("\nError while removing handler\n")
+	}
+	if false == routeTopic(t, publisher, "home/kitchen/temperature", defaultTopics, handlerTopics) {
+		t.Errorf("\nMessage delivered to removed handler\n")
+	}
+}
+
+func TestTopicHandlerNegative(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	handler := func(topic string, event ezmq.EZMQMessage) {}
+	if _, result := subscriber.AddTopicHandler("home/#/temperature", handler); ezmq.EZMQ_INVALID_TOPIC != result {
+		t.Errorf("\nHandler added for invalid topic\n")
+	}
+	if _, result := subscriber.AddTopicHandler(utils.Topic, nil); ezmq.EZMQ_ERROR != result {
+		t.Errorf("\nNil handler added\n")
+	}
+	if ezmq.EZMQ_ERROR != subscriber.RemoveTopicHandler(1000) {
+		t.Errorf("\nRemoved handler which is not added\n")
+	}
+	id, result := subscriber.AddTopicHandler(utils.Topic, handler)
+	if result != 0 || subscriber.RemoveTopicHandler(id) != 0 {
+		t.Errorf("\nError while adding and removing handler\n")
+	}
+	if ezmq.EZMQ_ERROR != subscriber.RemoveTopicHandler(id) {
+		t.Errorf("\nRemoved handler twice\n")
+	}
+	if _, result := subscriber.SubscribeForTopicWithHandler(utils.Topic, handler); ezmq.EZMQ_NOT_STARTED != result {
+		t.Errorf("\nSubscribed without start\n")
+	}
+}
+
+// Receive names of the handlers called for one message.
+func receiveHandlerNames(t *testing.T, publisher *ezmq.EZMQPublisher, names chan string, count int) []string {
+	var received []string
+	utils.PublishUntilReceived(t, func() {
+		publisher.PublishOnTopic("home/livingroom/temperature", utils.GetEvent())
+	}, func() bool {
+		select {
+		case name := <-names:
+			received = append(received, name)
+			// handlers of one message are called together
+			for len(received) < count {
+				received = append(received, <-names)
+			}
+			return true
+		default:
+			return false
+		}
+	})
+	return received
+}
+
+func TestTopicHandlerOrderAndRemove(t *testing.T) {
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	if nil == publisher || publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher\n")
+	}
+	defer publisher.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, subCB, subTopicCB)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic("home") != 0 {
+		t.Fatalf("\nError while subscribing\n")
+	}
+	defer subscriber.Stop()
+	names := make(chan string, 100)
+	first, _ := subscriber.AddTopicHandler("home/#", func(topic string, event ezmq.EZMQMessage) { names <- "first" })
+	subscriber.AddTopicHandler("home/+/temperature", func(topic string, event ezmq.EZMQMessage) { names <- "second" })
+	subscriber.AddTopicHandler("home", func(topic string, event ezmq.EZMQMessage) { names <- "third" })
+
+	received := receiveHandlerNames(t, publisher, names, 3)
+	if received[0] != "first" || received[1] != "second" || received[2] != "third" {
+		t.Errorf("\nHandlers called out of order: %v\n", received)
+	}
+	// wait till messages published while connecting are delivered
+	time.Sleep(500 * time.Millisecond)
+	for len(names) > 0 {
+		<-names
+	}
+
+	if subscriber.RemoveTopicHandler(first) != 0 {
+		t.Fatalf("\nError while removing handler\n")
+	}
+	received = receiveHandlerNames(t, publisher, names, 2)
+	if received[0] != "second" || received[1] != "third" {
+		t.Errorf("\nWrong handlers called after remove: %v\n", received)
+	}
+}
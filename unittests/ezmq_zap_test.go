@@ -0,0 +1,126 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package unittests
+
+import (
+	"go/ezmq"
+	"go/unittests/utils"
+
+	zmq "github.com/pebbe/zmq4"
+
+	"testing"
+	"time"
+)
+
+func TestClientKeyAllowlist(t *testing.T) {
+	serverPublicKey, serverSecretKey, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	allowedPublicKey, allowedSecretKey, _ := zmq.NewCurveKeypair()
+	deniedPublicKey, deniedSecretKey, _ := zmq.NewCurveKeypair()
+
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	failures := make(chan ezmq.EZMQAuthFailure, 10)
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	publisher.SetServerPrivateKey([]byte(serverSecretKey))
+	publisher.SetAuthFailureCallback(func(failure ezmq.EZMQAuthFailure) { failures <- failure })
+	if ezmq.EZMQ_KEY_INVALID != publisher.AddClientKey([]byte("invalid")) {
+		t.Errorf("\nInvalid key accepted\n")
+	}
+	if publisher.AddClientKey([]byte(allowedPublicKey)) != 0 {
+		t.Fatalf("\nError while adding client key: %v\n", publisher.GetLastError())
+	}
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	defer publisher.Stop()
+
+	subscriber := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	subscriber.SetClientKeys([]byte(allowedSecretKey), []byte(allowedPublicKey))
+	subscriber.SetServerPublicKey([]byte(serverPublicKey))
+	messages := subscriber.GetMessageChannel(10)
+	if subscriber.Start() != 0 || subscriber.SubscribeForTopic(utils.Topic) != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", subscriber.GetLastError())
+	}
+	defer subscriber.Stop()
+	receiveReverse(t, publisher, messages)
+
+	denied := ezmq.GetEZMQSubscriber(utils.Ip, utils.Port, nil, nil)
+	denied.SetClientKeys([]byte(deniedSecretKey), []byte(deniedPublicKey))
+	denied.SetServerPublicKey([]byte(serverPublicKey))
+	if denied.Start() != 0 {
+		t.Fatalf("\nError while starting subscriber: %v\n", denied.GetLastError())
+	}
+	defer denied.Stop()
+
+	select {
+	case failure := <-failures:
+		if failure.Mechanism != "CURVE" || failure.UserID != deniedPublicKey {
+			t.Errorf("\nWrong failure: %+v\n", failure)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("\nTimeout while waiting for authentication failure\n")
+	}
+
+	if publisher.RemoveClientKey([]byte(allowedPublicKey)) != 0 {
+		t.Errorf("\nError while removing client key: %v\n", publisher.GetLastError())
+	}
+	if ezmq.EZMQ_ERROR != publisher.RemoveClientKey([]byte(allowedPublicKey)) {
+		t.Errorf("\nRemoved client key twice\n")
+	}
+}
+
+func TestAuthenticationNotEnabled(t *testing.T) {
+	clientPublicKey, _, err := zmq.NewCurveKeypair()
+	if nil != err {
+		t.Skip("CURVE is not supported")
+	}
+	apiInstance := ezmq.GetInstance()
+	apiInstance.Initialize()
+	defer apiInstance.Terminate()
+
+	// ZAP handler of application
+	handler, err := apiInstance.GetContext().NewSocket(zmq.REP)
+	if nil != err {
+		t.Fatalf("\nError while creating socket\n")
+	}
+	handler.SetLinger(0)
+	defer handler.Close()
+	if nil != handler.Bind(ezmq.ZAP_ENDPOINT) {
+		t.Fatalf("\nError while binding ZAP handler\n")
+	}
+
+	publisher := ezmq.GetEZMQPublisher(utils.Port, startCB, stopCB, errorCB)
+	if publisher.Start() != 0 {
+		t.Fatalf("\nError while starting publisher: %v\n", publisher.GetLastError())
+	}
+	if ezmq.EZMQ_ERROR != publisher.AddClientKey([]byte(clientPublicKey)) {
+		t.Errorf("\nClient key added without authentication\n")
+	}
+	if ezmq.EZMQ_ERROR != publisher.AllowAddress("127.0.0.1") {
+		t.Errorf("\nAddress added without authentication\n")
+	}
+	publisher.Stop()
+	if publisher.AddClientKey([]byte(clientPublicKey)) != 0 {
+		t.Errorf("\nError while adding client key: %v\n", publisher.GetLastError())
+	}
+}
@@ -19,8 +19,18 @@ package utils
 
 import (
 	ezmq "go/ezmq"
+
+	"testing"
+	"time"
 )
 
+// Time to wait for a message in tests.
+const receiveTimeout = 5 * time.Second
+
+// Interval at which messages are published again while waiting, as messages
+// published before subscriber is connected are dropped.
+const republishInterval = 100 * time.Millisecond
+
 var Ip string = "localhost"
 var Port int = 5562
 var Topic string = "topic"
@@ -97,3 +107,34 @@ func GetByteDataEvent() ezmq.EZMQByteData {
 	bytes.ByteData = byteArray[:]
 	return bytes
 }
+
+func GetJSONDataEvent() ezmq.EZMQJSONData {
+	var jsonData ezmq.EZMQJSONData
+	jsonData.JSONData = []byte(`{"device":"device1","value":"20"}`)
+	return jsonData
+}
+
+func GetAMLDataEvent() ezmq.EZMQAMLData {
+	var amlData ezmq.EZMQAMLData
+	amlData.AMLData = []byte(`<CAEXFile FileName="gateway.aml"><InstanceHierarchy Name="Plant"/></CAEXFile>`)
+	return amlData
+}
+
+// Call publish every 100 ms till received returns true. Test fails if
+// received does not return true within 5 seconds. Received should check
+// channels without blocking.
+func PublishUntilReceived(t *testing.T, publish func(), received func() bool) {
+	t.Helper()
+	timeout := time.After(receiveTimeout)
+	ticker := time.NewTicker(republishInterval)
+	defer ticker.Stop()
+	publish()
+	for false == received() {
+		select {
+		case <-ticker.C:
+			publish()
+		case <-timeout:
+			t.Fatalf("\nTimeout while receiving\n")
+		}
+	}
+}